@@ -0,0 +1,65 @@
+package unsend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ErrCampaignSendRestricted is returned for campaign sends while a
+// RecipientOverride or recipient allowlist is set, since a campaign goes to a
+// whole contact book and its recipients can't be rewritten.
+var ErrCampaignSendRestricted = errors.New("[ERROR]: Campaigns can't be sent or scheduled while recipients are overridden or restricted")
+
+// Campaigns send one email to every contact in a contact book. A campaign is
+// created as a draft, then sent now or scheduled, and can be paused and
+// resumed while it is sending.
+type Campaigns interface {
+	CreateCampaign(ctx context.Context, request CreateCampaignRequest) (*GetCampaignResponse, error)
+	GetCampaign(ctx context.Context, request GetCampaignRequest) (*GetCampaignResponse, error)
+	ListCampaigns(ctx context.Context, request ListCampaignsRequest) (*ListCampaignsResponse, error)
+	UpdateCampaign(ctx context.Context, request UpdateCampaignRequest) (*GetCampaignResponse, error)
+	ScheduleCampaign(ctx context.Context, request ScheduleCampaignRequest) (*CampaignActionResponse, error)
+	SendCampaign(ctx context.Context, request SendCampaignRequest) (*CampaignActionResponse, error)
+	PauseCampaign(ctx context.Context, request PauseCampaignRequest) (*CampaignActionResponse, error)
+	ResumeCampaign(ctx context.Context, request ResumeCampaignRequest) (*CampaignActionResponse, error)
+	DeleteCampaign(ctx context.Context, request DeleteCampaignRequest) (*CampaignActionResponse, error)
+}
+
+type CampaignsImpl struct {
+	Client *Client
+}
+
+// prepare checks the sender when the client verifies senders, as SendEmail
+// does.
+func (r *CreateCampaignRequest) prepare(ctx context.Context, c *Client) error {
+	if c.SenderVerifier != nil {
+		return c.SenderVerifier.Verify(ctx, r.From)
+	}
+	return nil
+}
+
+// isCampaignSend reports whether req sends or schedules a campaign, including
+// a create with sendNow set.
+func isCampaignSend(req *http.Request) bool {
+	if req.Method != http.MethodPost {
+		return false
+	}
+
+	path := strings.TrimSuffix(req.URL.Path, "/")
+	if strings.HasSuffix(path, "/v1/campaigns") {
+		var campaign struct {
+			SendNow bool `json:"sendNow"`
+		}
+		json.Unmarshal(requestBody(req), &campaign)
+		return campaign.SendNow
+	}
+
+	_, action, ok := strings.Cut(path, "/v1/campaigns/")
+	if !ok {
+		return false
+	}
+	return strings.HasSuffix(action, "/send") || strings.HasSuffix(action, "/schedule")
+}
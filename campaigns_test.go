@@ -0,0 +1,258 @@
+package unsend_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+func TestCreateCampaign(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/campaigns" && r.Method == http.MethodPost {
+			json.NewDecoder(r.Body).Decode(&body)
+			w.Write([]byte(`{"id": "campaign123", "name": "June newsletter", "status": "DRAFT", "stats": {"total": 3}}`))
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := &unsend.Client{
+		Client:               &http.Client{},
+		DefaultFrom:          "news@unsend.dev",
+		DefaultContactBookId: "book123",
+	}
+	client.Campaigns = &unsend.CampaignsImpl{Client: client}
+	client.BaseUrl, _ = url.Parse(server.URL)
+
+	tests := []struct {
+		name           string
+		request        unsend.CreateCampaignRequest
+		expectedFrom   string
+		expectedBook   string
+		expectedErrMsg string
+	}{
+		{
+			name:         "Defaults from the client",
+			request:      unsend.CreateCampaignRequest{Name: "June newsletter", Subject: "June"},
+			expectedFrom: "news@unsend.dev",
+			expectedBook: "book123",
+		},
+		{
+			name:         "Request values win",
+			request:      unsend.CreateCampaignRequest{Name: "June newsletter", Subject: "June", From: "team@unsend.dev", ContactBookId: "book456"},
+			expectedFrom: "team@unsend.dev",
+			expectedBook: "book456",
+		},
+		{
+			name:           "Invalid request",
+			request:        unsend.CreateCampaignRequest{Subject: "June"},
+			expectedErrMsg: "[ERROR]: CreateCampaignRequest not valid; ['Name' is required]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body = nil
+			response, err := client.Campaigns.CreateCampaign(context.Background(), tt.request)
+			if tt.expectedErrMsg != "" {
+				if err == nil || err.Error() != tt.expectedErrMsg {
+					t.Errorf("expected error %q, got %v", tt.expectedErrMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if body["from"] != tt.expectedFrom || body["contactBookId"] != tt.expectedBook {
+				t.Errorf("expected from %s and contact book %s, got %v", tt.expectedFrom, tt.expectedBook, body)
+			}
+			if response.Id != "campaign123" || response.Status != unsend.CAMPAIGN_STATUS_DRAFT || response.Stats.Total != 3 {
+				t.Errorf("expected the draft campaign, got %+v", response)
+			}
+		})
+	}
+}
+
+func TestListCampaigns(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"campaigns": [{"id": "campaign123", "status": "PAUSED"}], "totalPage": 2}`))
+	}))
+	defer server.Close()
+
+	client := &unsend.Client{Client: &http.Client{}}
+	client.Campaigns = &unsend.CampaignsImpl{Client: client}
+	client.BaseUrl, _ = url.Parse(server.URL)
+
+	tests := []struct {
+		name          string
+		request       unsend.ListCampaignsRequest
+		expectedQuery string
+	}{
+		{"No filters", unsend.ListCampaignsRequest{}, ""},
+		{"Page and status", unsend.ListCampaignsRequest{Page: 2, Status: unsend.CAMPAIGN_STATUS_PAUSED}, "page=2&status=PAUSED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := client.Campaigns.ListCampaigns(context.Background(), tt.request)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if query.Encode() != tt.expectedQuery {
+				t.Errorf("expected query %q, got %q", tt.expectedQuery, query.Encode())
+			}
+			if response.TotalPage != 2 || response.Campaigns[0].Status != unsend.CAMPAIGN_STATUS_PAUSED {
+				t.Errorf("expected one paused campaign, got %+v", response)
+			}
+		})
+	}
+}
+
+func TestCampaignActions(t *testing.T) {
+	var called []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = append(called, r.Method+" "+r.URL.Path)
+		w.Write([]byte(`{"success": true}`))
+	}))
+	defer server.Close()
+
+	client := &unsend.Client{Client: &http.Client{}}
+	client.Campaigns = &unsend.CampaignsImpl{Client: client}
+	client.BaseUrl, _ = url.Parse(server.URL)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		call     func() (*unsend.CampaignActionResponse, error)
+		expected string
+	}{
+		{"Schedule", func() (*unsend.CampaignActionResponse, error) {
+			return client.Campaigns.ScheduleCampaign(ctx, unsend.ScheduleCampaignRequest{CampaignId: "campaign123", ScheduledAt: "2024-06-03T09:00:00Z"})
+		}, "POST /api/v1/campaigns/campaign123/schedule"},
+		{"Send", func() (*unsend.CampaignActionResponse, error) {
+			return client.Campaigns.SendCampaign(ctx, unsend.SendCampaignRequest{CampaignId: "campaign123"})
+		}, "POST /api/v1/campaigns/campaign123/send"},
+		{"Pause", func() (*unsend.CampaignActionResponse, error) {
+			return client.Campaigns.PauseCampaign(ctx, unsend.PauseCampaignRequest{CampaignId: "campaign123"})
+		}, "POST /api/v1/campaigns/campaign123/pause"},
+		{"Resume", func() (*unsend.CampaignActionResponse, error) {
+			return client.Campaigns.ResumeCampaign(ctx, unsend.ResumeCampaignRequest{CampaignId: "campaign123"})
+		}, "POST /api/v1/campaigns/campaign123/resume"},
+		{"Delete", func() (*unsend.CampaignActionResponse, error) {
+			return client.Campaigns.DeleteCampaign(ctx, unsend.DeleteCampaignRequest{CampaignId: "campaign123"})
+		}, "DELETE /api/v1/campaigns/campaign123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called = nil
+			response, err := tt.call()
+			if err != nil || !response.Success {
+				t.Fatalf("expected success, got %+v, %v", response, err)
+			}
+			if len(called) != 1 || called[0] != tt.expected {
+				t.Errorf("expected %s, got %v", tt.expected, called)
+			}
+		})
+	}
+
+	if _, err := client.Campaigns.ScheduleCampaign(ctx, unsend.ScheduleCampaignRequest{}); err == nil ||
+		err.Error() != "[ERROR]: ScheduleCampaignRequest not valid; ['CampaignId' is required 'ScheduledAt' is required]" {
+		t.Errorf("expected a validation error, got %v", err)
+	}
+}
+
+func TestCampaignsDryRun(t *testing.T) {
+	server, requests := newSandboxTestServer(t)
+
+	client, err := unsend.NewClient(
+		unsend.WithApiKey("test-api-key"),
+		unsend.WithBaseUrl(server.URL),
+		unsend.WithConfigFile(filepath.Join(t.TempDir(), "missing.toml")),
+		unsend.WithDryRun(),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	campaign, err := client.Campaigns.CreateCampaign(context.Background(), unsend.CreateCampaignRequest{
+		Name: "June newsletter", From: "news@unsend.dev", Subject: "June", ContactBookId: "book123",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if campaign.Id != "sandbox-1" || campaign.Status != unsend.CAMPAIGN_STATUS_DRAFT || campaign.Name != "June newsletter" {
+		t.Errorf("expected a synthetic draft campaign, got %+v", campaign)
+	}
+
+	sent, err := client.Campaigns.SendCampaign(context.Background(), unsend.SendCampaignRequest{CampaignId: campaign.Id})
+	if err != nil || !sent.Success {
+		t.Errorf("expected a synthetic success, got %+v, %v", sent, err)
+	}
+	if len(requests()) != 0 {
+		t.Errorf("expected no requests to reach the API, got %v", requests())
+	}
+}
+
+func TestCampaignSendsRefusedWhileRecipientsRestricted(t *testing.T) {
+	server, requests := newSandboxTestServer(t)
+
+	tests := []struct {
+		name   string
+		option unsend.ClientOption
+	}{
+		{"Recipient override", unsend.WithRecipientOverride(unsend.RecipientOverride{To: []string{"qa@unsend.dev"}})},
+		{"Recipient allowlist", unsend.WithRecipientAllowlist("unsend.dev")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := unsend.NewClient(
+				unsend.WithApiKey("test-api-key"),
+				unsend.WithBaseUrl(server.URL),
+				unsend.WithConfigFile(filepath.Join(t.TempDir(), "missing.toml")),
+				tt.option,
+			)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			ctx := context.Background()
+			before := len(requests())
+
+			if _, err := client.Campaigns.SendCampaign(ctx, unsend.SendCampaignRequest{CampaignId: "campaign123"}); !errors.Is(err, unsend.ErrCampaignSendRestricted) {
+				t.Errorf("expected SendCampaign to be refused, got %v", err)
+			}
+			if _, err := client.Campaigns.ScheduleCampaign(ctx, unsend.ScheduleCampaignRequest{CampaignId: "campaign123", ScheduledAt: "2024-06-03T09:00:00Z"}); !errors.Is(err, unsend.ErrCampaignSendRestricted) {
+				t.Errorf("expected ScheduleCampaign to be refused, got %v", err)
+			}
+			campaign := unsend.CreateCampaignRequest{Name: "June newsletter", From: "news@unsend.dev", Subject: "June", ContactBookId: "book123", SendNow: true}
+			if _, err := client.Campaigns.CreateCampaign(ctx, campaign); !errors.Is(err, unsend.ErrCampaignSendRestricted) {
+				t.Errorf("expected CreateCampaign with SendNow to be refused, got %v", err)
+			}
+			if len(requests()) != before {
+				t.Errorf("expected no requests to reach the API, got %v", requests()[before:])
+			}
+
+			campaign.SendNow = false
+			if _, err := client.Campaigns.CreateCampaign(ctx, campaign); err != nil {
+				t.Errorf("expected a draft campaign to be created, got %v", err)
+			}
+			if _, err := client.Campaigns.PauseCampaign(ctx, unsend.PauseCampaignRequest{CampaignId: "campaign123"}); err != nil {
+				t.Errorf("expected PauseCampaign to be allowed, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package unsend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// CAPABILITIES_PATH is the OpenAPI document the server publishes for its API.
+// The paths it lists are the features the server supports.
+const CAPABILITIES_PATH = "api/v1/doc"
+
+var ErrUnsupportedByServer = errors.New("[ERROR]: Operation not supported by the Unsend server")
+
+// Capabilities are the features a server supports, read from its OpenAPI
+// document. When the server doesn't publish one, Detected is false and every
+// feature is assumed to be available.
+type Capabilities struct {
+	Detected bool
+	// Version is the API version the server reports.
+	Version string
+
+	ScheduledEmails    bool
+	BatchSend          bool
+	ContactListing     bool
+	DomainVerification bool
+	Campaigns          bool
+
+	operations map[string]bool
+}
+
+// Supports reports whether the server has the operation, given as a method
+// and a path relative to /v1 with {placeholders}, such as
+// "POST /emails/batch".
+func (c *Capabilities) Supports(method, path string) bool {
+	if !c.Detected {
+		return true
+	}
+	return c.operations[operationKey(method, path)]
+}
+
+// allCapabilities is assumed when the server can't be probed.
+func allCapabilities() *Capabilities {
+	return &Capabilities{
+		ScheduledEmails:    true,
+		BatchSend:          true,
+		ContactListing:     true,
+		DomainVerification: true,
+		Campaigns:          true,
+	}
+}
+
+// capabilityRequirements lists the SDK operations that need an optional
+// feature. Operations not listed are always attempted.
+var capabilityRequirements = map[string]func(*Capabilities) bool{
+	"UpdateSchedule": func(c *Capabilities) bool { return c.ScheduledEmails },
+	"CancelSchedule": func(c *Capabilities) bool { return c.ScheduledEmails },
+	"VerifyDomain":   func(c *Capabilities) bool { return c.DomainVerification },
+
+	"CreateCampaign":   func(c *Capabilities) bool { return c.Campaigns },
+	"GetCampaign":      func(c *Capabilities) bool { return c.Campaigns },
+	"ListCampaigns":    func(c *Capabilities) bool { return c.Campaigns },
+	"UpdateCampaign":   func(c *Capabilities) bool { return c.Campaigns },
+	"ScheduleCampaign": func(c *Capabilities) bool { return c.Campaigns },
+	"SendCampaign":     func(c *Capabilities) bool { return c.Campaigns },
+	"PauseCampaign":    func(c *Capabilities) bool { return c.Campaigns },
+	"ResumeCampaign":   func(c *Capabilities) bool { return c.Campaigns },
+	"DeleteCampaign":   func(c *Capabilities) bool { return c.Campaigns },
+}
+
+type capabilityProbe struct {
+	mu           sync.Mutex
+	capabilities *Capabilities
+}
+
+// Capabilities probes the server once and returns what it supports. Network
+// errors are returned and retried on the next call; a server without an
+// OpenAPI document gives Capabilities with Detected false.
+func (c *Client) Capabilities(ctx context.Context) (*Capabilities, error) {
+	c.capabilities.mu.Lock()
+	defer c.capabilities.mu.Unlock()
+
+	if c.capabilities.capabilities != nil {
+		return c.capabilities.capabilities, nil
+	}
+
+	req, err := c.NewRequestWithContext(ctx, http.MethodGet, CAPABILITIES_PATH, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	exchange, err := c.send(req)
+	var apiErr *APIError
+	switch {
+	case errors.As(err, &apiErr) && apiErr.StatusCode < 500 && apiErr.StatusCode != http.StatusTooManyRequests:
+		c.capabilities.capabilities = allCapabilities()
+	case err != nil:
+		return nil, err
+	default:
+		capabilities, err := parseCapabilities(exchange.body)
+		if err != nil {
+			capabilities = allCapabilities()
+		}
+		c.capabilities.capabilities = capabilities
+	}
+
+	if c.Logger != nil {
+		c.Logger.DebugContext(ctx, "unsend server capabilities",
+			"detected", c.capabilities.capabilities.Detected, "version", c.capabilities.capabilities.Version)
+	}
+	return c.capabilities.capabilities, nil
+}
+
+func parseCapabilities(body []byte) (*Capabilities, error) {
+	var document struct {
+		Info struct {
+			Version string `json:"version"`
+		} `json:"info"`
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return nil, err
+	}
+	if len(document.Paths) == 0 {
+		return nil, fmt.Errorf("no paths in API description")
+	}
+
+	capabilities := &Capabilities{
+		Detected:   true,
+		Version:    document.Info.Version,
+		operations: map[string]bool{},
+	}
+	for path, methods := range document.Paths {
+		for method := range methods {
+			capabilities.operations[operationKey(method, path)] = true
+		}
+	}
+
+	capabilities.ScheduledEmails = capabilities.Supports(http.MethodPost, "/emails/{emailId}/cancel")
+	capabilities.BatchSend = capabilities.Supports(http.MethodPost, "/emails/batch")
+	capabilities.ContactListing = capabilities.Supports(http.MethodGet, "/contactBooks/{contactBookId}/contacts")
+	capabilities.DomainVerification = capabilities.Supports(http.MethodPut, "/domains/{id}/verify")
+	capabilities.Campaigns = capabilities.Supports(http.MethodPost, "/campaigns")
+	return capabilities, nil
+}
+
+// operationKey normalises an operation so the prefix before /v1 and the names
+// of path parameters don't matter: "POST /emails/{}/cancel".
+func operationKey(method, path string) string {
+	if _, rest, ok := strings.Cut(path, "v1/"); ok {
+		path = rest
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") || strings.HasPrefix(segment, ":") {
+			segments[i] = "{}"
+		}
+	}
+	return strings.ToUpper(method) + " /" + strings.Join(segments, "/")
+}
+
+// checkCapabilities fails req fast when the server is known not to support
+// its operation. Probe failures never block a request.
+func (c *Client) checkCapabilities(req *http.Request) error {
+	if !c.CapabilityDetection {
+		return nil
+	}
+
+	operation := endpointOperation(req.Method, req.URL.Path)
+	requirement, ok := capabilityRequirements[operation]
+	if !ok {
+		return nil
+	}
+
+	capabilities, err := c.Capabilities(req.Context())
+	if err != nil {
+		if c.Logger != nil {
+			c.Logger.DebugContext(req.Context(), "unsend capability probe failed", "error", err)
+		}
+		return nil
+	}
+
+	if !requirement(capabilities) {
+		return fmt.Errorf("%w: %s", ErrUnsupportedByServer, operation)
+	}
+	return nil
+}
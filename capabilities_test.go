@@ -0,0 +1,110 @@
+package unsend_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+const apiDescription = `{
+	"openapi": "3.0.0",
+	"info": {"title": "Unsend API", "version": "1.3.0"},
+	"paths": {
+		"/v1/emails": {"post": {}},
+		"/v1/emails/{emailId}": {"get": {}},
+		"/v1/emails/batch": {"post": {}},
+		"/v1/contactBooks/{contactBookId}/contacts": {"post": {}},
+		"/v1/domains": {"get": {}, "post": {}}
+	}
+}`
+
+func newCapabilitiesClient(t *testing.T, description string, calls map[string]*atomic.Int32) *unsend.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if counter, ok := calls[r.URL.Path]; ok {
+			counter.Add(1)
+		}
+		switch {
+		case r.URL.Path == "/api/v1/doc" && description == "":
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/api/v1/doc":
+			w.Write([]byte(description))
+		default:
+			w.Write([]byte(`{"emailId": "email123", "message": "ok"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := unsend.NewClient(
+		unsend.WithApiKey("test-api-key"),
+		unsend.WithBaseUrl(server.URL),
+		unsend.WithConfigFile(filepath.Join(t.TempDir(), "missing.toml")),
+		unsend.WithCapabilityDetection(),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	return client
+}
+
+func TestCapabilityDetection(t *testing.T) {
+	calls := map[string]*atomic.Int32{
+		"/api/v1/doc":                    {},
+		"/api/v1/emails/email123/cancel": {},
+	}
+	client := newCapabilitiesClient(t, apiDescription, calls)
+	ctx := context.Background()
+
+	_, err := client.Emails.CancelSchedule(ctx, unsend.CancelScheduleRequest{EmailId: "email123"})
+	if !errors.Is(err, unsend.ErrUnsupportedByServer) {
+		t.Errorf("expected ErrUnsupportedByServer, got %v", err)
+	}
+	if _, err := client.Domains.VerifyDomain(ctx, unsend.VerifyDomainRequest{DomainId: 1}); !errors.Is(err, unsend.ErrUnsupportedByServer) {
+		t.Errorf("expected ErrUnsupportedByServer, got %v", err)
+	}
+	if _, err := client.Campaigns.ListCampaigns(ctx, unsend.ListCampaignsRequest{}); !errors.Is(err, unsend.ErrUnsupportedByServer) {
+		t.Errorf("expected ErrUnsupportedByServer, got %v", err)
+	}
+	if _, err := client.Emails.SendEmail(ctx, unsend.SendEmailRequest{To: []string{"a@b.c"}, From: "hello@unsend.dev", Text: "Hi"}); err != nil {
+		t.Errorf("expected supported operation to succeed, got %v", err)
+	}
+
+	capabilities, err := client.Capabilities(ctx)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	got := []bool{capabilities.Detected, capabilities.ScheduledEmails, capabilities.BatchSend, capabilities.ContactListing, capabilities.DomainVerification, capabilities.Campaigns}
+	if want := []bool{true, false, true, false, false, false}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected detected, batch send only capabilities, got %+v", capabilities)
+	}
+	if !capabilities.Supports(http.MethodGet, "/api/v1/emails/{id}") || capabilities.Supports(http.MethodDelete, "/v1/emails/{id}") {
+		t.Errorf("expected Supports to match the described operations")
+	}
+	if capabilities.Version != "1.3.0" {
+		t.Errorf("expected version 1.3.0, got %q", capabilities.Version)
+	}
+
+	if calls["/api/v1/doc"].Load() != 1 || calls["/api/v1/emails/email123/cancel"].Load() != 0 {
+		t.Errorf("expected one probe and no unsupported calls, got %d and %d",
+			calls["/api/v1/doc"].Load(), calls["/api/v1/emails/email123/cancel"].Load())
+	}
+}
+
+func TestCapabilityDetectionWithoutDescription(t *testing.T) {
+	client := newCapabilitiesClient(t, "", map[string]*atomic.Int32{})
+
+	if _, err := client.Emails.CancelSchedule(context.Background(), unsend.CancelScheduleRequest{EmailId: "email123"}); err != nil {
+		t.Errorf("expected operations to be attempted when the server can't be probed, got %v", err)
+	}
+
+	capabilities, err := client.Capabilities(context.Background())
+	if err != nil || capabilities.Detected || !capabilities.Campaigns {
+		t.Errorf("expected undetected capabilities with every feature assumed, got %+v, %v", capabilities, err)
+	}
+}
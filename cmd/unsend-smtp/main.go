@@ -0,0 +1,105 @@
+// Command unsend-smtp is an SMTP server that sends the messages it receives
+// through the Unsend API, for applications that can only send email over SMTP.
+//
+//	unsend-smtp [-listen 127.0.0.1:2525] [-username user -password pass]
+//
+// Messages are parsed into a SendEmailRequest: To, Cc and Reply-To come from
+// the headers, and non-body MIME parts become attachments. The envelope
+// decides who gets the email: To and Cc addresses that aren't envelope
+// recipients are dropped, and envelope recipients not listed there are sent
+// as Bcc. When -username is set clients must
+// AUTH (PLAIN or LOGIN) before sending. The server does not offer STARTTLS, so
+// keep it on a loopback or otherwise private address.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+const (
+	envKeyUsername = "UNSEND_SMTP_USERNAME"
+	envKeyPassword = "UNSEND_SMTP_PASSWORD"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stderr))
+}
+
+func run(ctx context.Context, args []string, stderr io.Writer) int {
+	flags := flag.NewFlagSet("unsend-smtp", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	listen := flags.String("listen", "127.0.0.1:2525", "address to accept SMTP connections on")
+	hostname := flags.String("hostname", "localhost", "name announced in the SMTP greeting")
+	username := flags.String("username", os.Getenv(envKeyUsername), "username clients must AUTH with (default $"+envKeyUsername+")")
+	password := flags.String("password", os.Getenv(envKeyPassword), "password clients must AUTH with (default $"+envKeyPassword+")")
+	maxSize := flags.Int64("max-size", defaultMaxMessageBytes, "largest accepted message in bytes")
+	apiKey := flags.String("api-key", "", "API key (default $"+unsend.ENV_KEY_API_KEY+")")
+	baseUrl := flags.String("base-url", "", "API base URL including /api (default $"+unsend.ENV_KEY_BASE_URL+")")
+	profile := flags.String("profile", "", "config file profile (default $"+unsend.ENV_KEY_PROFILE+")")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if (*username == "") != (*password == "") {
+		fmt.Fprintln(stderr, "-username and -password must be set together")
+		return 2
+	}
+
+	var opts []unsend.ClientOption
+	if *apiKey != "" {
+		opts = append(opts, unsend.WithApiKey(*apiKey))
+	}
+	if *baseUrl != "" {
+		opts = append(opts, unsend.WithBaseUrl(*baseUrl))
+	}
+	if *profile != "" {
+		opts = append(opts, unsend.WithProfile(*profile))
+	}
+
+	client, err := unsend.NewClient(opts...)
+	if err != nil {
+		fmt.Fprintf(stderr, "[ERROR] - %s\n", err.Error())
+		return 1
+	}
+
+	logger := slog.New(slog.NewTextHandler(stderr, nil))
+
+	listener, err := net.Listen("tcp", *listen)
+	if err != nil {
+		fmt.Fprintf(stderr, "[ERROR] - %s\n", err.Error())
+		return 1
+	}
+
+	if *username == "" {
+		logger.Warn("no -username set, accepting mail from any client that can connect")
+	}
+	logger.Info("listening for SMTP", "address", listener.Addr().String())
+
+	srv := &server{
+		emails:          client.Emails,
+		hostname:        *hostname,
+		username:        *username,
+		password:        *password,
+		maxMessageBytes: *maxSize,
+		logger:          logger,
+	}
+	if err := srv.serve(ctx, listener); err != nil {
+		fmt.Fprintf(stderr, "[ERROR] - %s\n", err.Error())
+		return 1
+	}
+
+	return 0
+}
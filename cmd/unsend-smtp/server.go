@@ -0,0 +1,556 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+const (
+	defaultMaxMessageBytes = 10 << 20
+	defaultMaxRecipients   = 100
+	commandTimeout         = 5 * time.Minute
+	sendTimeout            = time.Minute
+	// RFC 5321 4.5.3.1: line lengths include the CRLF.
+	maxCommandLine = 512
+	maxTextLine    = 1000
+)
+
+var errLineTooLong = errors.New("line too long")
+
+// server accepts SMTP submissions and sends each message through Emails.
+// Authentication is required when username is set.
+type server struct {
+	emails          unsend.Emails
+	hostname        string
+	username        string
+	password        string
+	maxMessageBytes int64
+	logger          *slog.Logger
+
+	wg sync.WaitGroup
+}
+
+// smtpError is an SMTP reply sent instead of the usual success reply.
+type smtpError struct {
+	code    int
+	message string
+}
+
+func (e *smtpError) Error() string {
+	return fmt.Sprintf("%d %s", e.code, e.message)
+}
+
+// serve accepts connections on listener until ctx is done, then waits for
+// open sessions to finish.
+func (s *server) serve(ctx context.Context, listener net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			s.wg.Wait()
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(ctx, conn)
+		}()
+	}
+}
+
+type session struct {
+	server        *server
+	conn          *lineLimitConn
+	text          *textproto.Conn
+	greeted       bool
+	authenticated bool
+	// inTransaction is set by MAIL, whose sender may be the empty null path.
+	inTransaction bool
+	from          string
+	recipients    []string
+}
+
+// lineLimitConn fails reads once a line is longer than max bytes, so a client
+// can't make the server buffer a line without end. bufio hands back the start
+// of the line without the error, so readers check exceeded instead.
+type lineLimitConn struct {
+	net.Conn
+	max      int
+	length   int
+	exceeded bool
+}
+
+func (c *lineLimitConn) Read(p []byte) (int, error) {
+	if c.exceeded {
+		return 0, errLineTooLong
+	}
+
+	n, err := c.Conn.Read(p)
+	for i := 0; i < n; i++ {
+		if p[i] == '\n' {
+			c.length = 0
+			continue
+		}
+		c.length++
+		// The LF isn't counted yet, so a full line leaves room for it.
+		if c.length >= c.max {
+			c.exceeded = true
+			return i, errLineTooLong
+		}
+	}
+	return n, err
+}
+
+func (s *server) handle(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	// Idle sessions would otherwise hold up shutdown until commandTimeout.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	limited := &lineLimitConn{Conn: conn, max: maxCommandLine}
+	sess := &session{server: s, conn: limited, text: textproto.NewConn(limited)}
+	sess.reply(220, s.hostname+" ESMTP unsend-smtp ready")
+
+	for {
+		conn.SetDeadline(time.Now().Add(commandTimeout))
+		line, err := sess.text.ReadLine()
+		if sess.conn.exceeded {
+			sess.reply(500, "5.5.6 Line too long")
+			return
+		}
+		if err != nil {
+			return
+		}
+
+		verb, arg, _ := strings.Cut(line, " ")
+		arg = strings.TrimSpace(arg)
+
+		switch strings.ToUpper(verb) {
+		case "HELO":
+			sess.greeted = true
+			sess.reset()
+			sess.reply(250, s.hostname)
+		case "EHLO":
+			sess.greeted = true
+			sess.reset()
+			sess.ehlo()
+		case "AUTH":
+			sess.auth(arg)
+		case "MAIL":
+			sess.mail(arg)
+		case "RCPT":
+			sess.rcpt(arg)
+		case "DATA":
+			if !sess.data(ctx) {
+				return
+			}
+		case "RSET":
+			sess.reset()
+			sess.reply(250, "2.0.0 Ok")
+		case "NOOP":
+			sess.reply(250, "2.0.0 Ok")
+		case "VRFY":
+			sess.reply(252, "2.5.0 Cannot verify user")
+		case "QUIT":
+			sess.reply(221, "2.0.0 Bye")
+			return
+		default:
+			sess.reply(502, "5.5.2 Command not recognized")
+		}
+	}
+}
+
+func (sess *session) reply(code int, message string) {
+	sess.text.PrintfLine("%d %s", code, message)
+}
+
+func (sess *session) replyError(err *smtpError) {
+	sess.reply(err.code, err.message)
+}
+
+func (sess *session) reset() {
+	sess.inTransaction = false
+	sess.from = ""
+	sess.recipients = nil
+}
+
+func (sess *session) ehlo() {
+	lines := []string{
+		sess.server.hostname,
+		"8BITMIME",
+		fmt.Sprintf("SIZE %d", sess.server.maxMessageBytes),
+	}
+	if sess.server.username != "" {
+		lines = append(lines, "AUTH PLAIN LOGIN")
+	}
+
+	for i, line := range lines {
+		separator := "-"
+		if i == len(lines)-1 {
+			separator = " "
+		}
+		sess.text.PrintfLine("250%s%s", separator, line)
+	}
+}
+
+func (sess *session) auth(arg string) {
+	switch {
+	case sess.server.username == "":
+		sess.reply(502, "5.5.1 AUTH not available")
+		return
+	case sess.authenticated:
+		sess.reply(503, "5.5.1 Already authenticated")
+		return
+	case sess.inTransaction:
+		sess.reply(503, "5.5.1 AUTH not allowed during a mail transaction")
+		return
+	}
+
+	mechanism, initial, _ := strings.Cut(arg, " ")
+
+	var username, password string
+	var err error
+	switch strings.ToUpper(mechanism) {
+	case "PLAIN":
+		username, password, err = sess.authPlain(initial)
+	case "LOGIN":
+		username, password, err = sess.authLogin(initial)
+	default:
+		sess.reply(504, "5.5.4 Unrecognized authentication type")
+		return
+	}
+	if err != nil {
+		sess.reply(501, "5.5.2 "+err.Error())
+		return
+	}
+
+	if !sess.server.checkCredentials(username, password) {
+		sess.reply(535, "5.7.8 Authentication credentials invalid")
+		return
+	}
+
+	sess.authenticated = true
+	sess.reply(235, "2.7.0 Authentication successful")
+}
+
+func (sess *session) authPlain(initial string) (string, string, error) {
+	if initial == "" {
+		response, err := sess.challenge("")
+		if err != nil {
+			return "", "", err
+		}
+		initial = response
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(initial)
+	if err != nil {
+		return "", "", errors.New("Invalid base64 data")
+	}
+
+	// authzid NUL authcid NUL passwd
+	fields := bytes.Split(decoded, []byte{0})
+	if len(fields) != 3 {
+		return "", "", errors.New("Invalid PLAIN response")
+	}
+	return string(fields[1]), string(fields[2]), nil
+}
+
+func (sess *session) authLogin(initial string) (string, string, error) {
+	username := initial
+	if username == "" {
+		response, err := sess.challenge("Username:")
+		if err != nil {
+			return "", "", err
+		}
+		username = response
+	}
+
+	password, err := sess.challenge("Password:")
+	if err != nil {
+		return "", "", err
+	}
+
+	decodedUsername, err := base64.StdEncoding.DecodeString(username)
+	if err != nil {
+		return "", "", errors.New("Invalid base64 data")
+	}
+	decodedPassword, err := base64.StdEncoding.DecodeString(password)
+	if err != nil {
+		return "", "", errors.New("Invalid base64 data")
+	}
+	return string(decodedUsername), string(decodedPassword), nil
+}
+
+// challenge sends a 334 prompt and returns the client's response.
+func (sess *session) challenge(prompt string) (string, error) {
+	sess.reply(334, base64.StdEncoding.EncodeToString([]byte(prompt)))
+
+	line, err := sess.text.ReadLine()
+	if sess.conn.exceeded {
+		return "", errLineTooLong
+	}
+	if err != nil {
+		return "", err
+	}
+	if line == "*" {
+		return "", errors.New("Authentication cancelled")
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func (s *server) checkCredentials(username, password string) bool {
+	usernameOk := subtle.ConstantTimeCompare([]byte(username), []byte(s.username)) == 1
+	passwordOk := subtle.ConstantTimeCompare([]byte(password), []byte(s.password)) == 1
+	return usernameOk && passwordOk
+}
+
+func (sess *session) mail(arg string) {
+	switch {
+	case !sess.greeted:
+		sess.reply(503, "5.5.1 Send HELO or EHLO first")
+		return
+	case sess.server.username != "" && !sess.authenticated:
+		sess.reply(530, "5.7.0 Authentication required")
+		return
+	case sess.inTransaction:
+		sess.reply(503, "5.5.1 Sender already specified")
+		return
+	}
+
+	// An empty address is the null reverse path, MAIL FROM:<>, used for
+	// bounces; the message's From header then names the sender.
+	address, ok := pathArgument(arg, "FROM:")
+	if !ok {
+		sess.reply(501, "5.5.4 Syntax: MAIL FROM:<address>")
+		return
+	}
+
+	sess.inTransaction = true
+	sess.from = address
+	sess.reply(250, "2.1.0 Ok")
+}
+
+func (sess *session) rcpt(arg string) {
+	if !sess.inTransaction {
+		sess.reply(503, "5.5.1 Need MAIL before RCPT")
+		return
+	}
+	if len(sess.recipients) >= defaultMaxRecipients {
+		sess.reply(452, "4.5.3 Too many recipients")
+		return
+	}
+
+	address, ok := pathArgument(arg, "TO:")
+	if !ok || address == "" {
+		sess.reply(501, "5.5.4 Syntax: RCPT TO:<address>")
+		return
+	}
+
+	sess.recipients = append(sess.recipients, address)
+	sess.reply(250, "2.1.5 Ok")
+}
+
+// data reads and sends a message. It returns false if the connection can't be
+// used any more.
+func (sess *session) data(ctx context.Context) bool {
+	if len(sess.recipients) == 0 {
+		sess.reply(503, "5.5.1 Need RCPT before DATA")
+		return true
+	}
+
+	sess.reply(354, "End data with <CR><LF>.<CR><LF>")
+
+	sess.conn.max = maxTextLine
+	defer func() { sess.conn.max = maxCommandLine }()
+
+	limit := sess.server.maxMessageBytes
+	dot := sess.text.DotReader()
+	body, err := io.ReadAll(io.LimitReader(dot, limit+1))
+	if sess.conn.exceeded {
+		sess.reply(500, "5.5.6 Line too long")
+		return false
+	}
+	if err != nil {
+		return false
+	}
+	if int64(len(body)) > limit {
+		// Discard the rest of the message so the session stays in sync.
+		io.Copy(io.Discard, dot)
+		sess.reset()
+		sess.reply(552, "5.3.4 Message size exceeds fixed maximum message size")
+		return true
+	}
+
+	emailId, smtpErr := sess.server.send(ctx, sess.from, sess.recipients, body)
+	sess.reset()
+	if smtpErr != nil {
+		sess.replyError(smtpErr)
+		return true
+	}
+
+	sess.reply(250, "2.0.0 Ok: queued as "+emailId)
+	return true
+}
+
+func (s *server) send(ctx context.Context, from string, recipients []string, body []byte) (string, *smtpError) {
+	request, err := unsend.ParseMessage(bytes.NewReader(body))
+	if err != nil {
+		return "", &smtpError{554, "5.6.0 " + err.Error()}
+	}
+
+	applyEnvelope(request, from, recipients)
+
+	if err := request.Validate(); err != nil {
+		return "", &smtpError{554, "5.6.0 Message not accepted: " + strings.Join(err.Errors, "; ")}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	response, err := s.emails.SendEmail(ctx, *request)
+	if err != nil {
+		s.logger.Warn("failed to send email", "from", from, "recipients", len(recipients), "error", err)
+		return "", mapError(err)
+	}
+
+	s.logger.Info("sent email", "email_id", response.EmailId, "recipients", len(recipients))
+	return response.EmailId, nil
+}
+
+// applyEnvelope makes the SMTP envelope decide who gets the email. To and Cc
+// keep only the header addresses that are also envelope recipients, so a
+// client that splits Bcc into its own transaction doesn't deliver to the To
+// list twice. The other recipients become Bcc (or To, if none are left). The
+// envelope sender is used when there's no From header.
+func applyEnvelope(request *unsend.SendEmailRequest, from string, recipients []string) {
+	if request.From == "" {
+		request.From = from
+	}
+
+	envelope := map[string]bool{}
+	for _, address := range recipients {
+		envelope[envelopeAddress(address)] = true
+	}
+
+	listed := map[string]bool{}
+	keep := func(addresses []string) []string {
+		var kept []string
+		for _, address := range addresses {
+			if envelope[envelopeAddress(address)] {
+				kept = append(kept, address)
+				listed[envelopeAddress(address)] = true
+			}
+		}
+		return kept
+	}
+	request.To = keep(request.To)
+	request.Cc = keep(request.Cc)
+
+	var rest []string
+	for _, address := range recipients {
+		if !listed[envelopeAddress(address)] {
+			rest = append(rest, address)
+		}
+	}
+
+	// Bcc comes from the envelope; a Bcc header in the message is not trusted
+	// to match who the client actually asked us to deliver to.
+	request.Bcc = nil
+	if len(request.To) == 0 {
+		request.To = rest
+	} else {
+		request.Bcc = rest
+	}
+}
+
+// envelopeAddress returns the lower-cased bare address of a header or
+// envelope address, such as a@b.c for "A" <A@b.c>.
+func envelopeAddress(address string) string {
+	if parsed, err := mail.ParseAddress(address); err == nil {
+		address = parsed.Address
+	}
+	return strings.ToLower(strings.TrimSpace(address))
+}
+
+// mapError turns a SendEmail error into an SMTP reply. Problems the client
+// can't fix by retrying are permanent (5xx); the rest are transient (4xx) so
+// the client queues the message and tries again.
+func mapError(err error) *smtpError {
+	var senderErr *unsend.SenderDomainError
+	if errors.As(err, &senderErr) {
+		return &smtpError{553, "5.7.1 Sender domain not allowed: " + senderErr.Domain}
+	}
+
+	var apiErr *unsend.APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.StatusCode == http.StatusTooManyRequests:
+			return &smtpError{451, "4.7.0 Rate limited by the Unsend API, try again later"}
+		case apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden:
+			return &smtpError{451, "4.7.1 Relay is not authorized by the Unsend API, try again later"}
+		case apiErr.StatusCode >= 500:
+			return &smtpError{451, "4.3.0 Unsend API unavailable, try again later"}
+		case apiErr.StatusCode >= 400:
+			return &smtpError{554, "5.6.0 Message rejected by the Unsend API: " + oneLine(apiErr.Body)}
+		}
+	}
+
+	// A send cut short by sendTimeout or shutdown can be tried again.
+	if unsend.IsTemporary(err) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return &smtpError{451, "4.4.1 Unable to reach the Unsend API, try again later"}
+	}
+	return &smtpError{554, "5.6.0 Message not accepted: " + oneLine(err.Error())}
+}
+
+func oneLine(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	if len(value) > 200 {
+		value = value[:200]
+	}
+	return value
+}
+
+// pathArgument parses "FROM:<address> PARAMS" or "TO:<address> PARAMS".
+func pathArgument(arg string, prefix string) (string, bool) {
+	if len(arg) < len(prefix) || !strings.EqualFold(arg[:len(prefix)], prefix) {
+		return "", false
+	}
+
+	path := strings.TrimSpace(arg[len(prefix):])
+	path, _, _ = strings.Cut(path, " ")
+	if !strings.HasPrefix(path, "<") || !strings.HasSuffix(path, ">") {
+		return "", false
+	}
+
+	address := path[1 : len(path)-1]
+	if address == "" {
+		return "", true
+	}
+	if _, err := mail.ParseAddress(address); err != nil {
+		return "", false
+	}
+	return address, true
+}
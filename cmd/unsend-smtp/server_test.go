@@ -0,0 +1,351 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/textproto"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+type fakeEmails struct {
+	unsend.Emails
+
+	mu       sync.Mutex
+	requests []unsend.SendEmailRequest
+	err      error
+}
+
+func (f *fakeEmails) SendEmail(ctx context.Context, request unsend.SendEmailRequest) (*unsend.EmailIdResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.requests = append(f.requests, request)
+	return &unsend.EmailIdResponse{EmailId: "email-1"}, nil
+}
+
+func startServer(t *testing.T, emails unsend.Emails) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	srv := &server{
+		emails:          emails,
+		hostname:        "localhost",
+		username:        "relay",
+		password:        "secret",
+		maxMessageBytes: 1 << 16,
+		logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	done := make(chan struct{})
+	go func() {
+		srv.serve(ctx, listener)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	return listener.Addr().String()
+}
+
+const multipartMessage = "From: App <app@example.com>\r\n" +
+	"To: alice@example.com\r\n" +
+	"Cc: bob@example.com\r\n" +
+	"Reply-To: support@example.com\r\n" +
+	"Subject: =?UTF-8?Q?Caf=C3=A9_order?=\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=outer\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: multipart/alternative; boundary=inner\r\n" +
+	"\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"\r\n" +
+	"Hello\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"<p>Hello=3D</p>\r\n" +
+	"--inner--\r\n" +
+	"--outer\r\n" +
+	"Content-Type: text/csv; name=report.csv\r\n" +
+	"Content-Disposition: attachment; filename=report.csv\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"YSxiCjEsMgo=\r\n" +
+	"--outer--\r\n"
+
+func TestRelaySendsMessage(t *testing.T) {
+	emails := &fakeEmails{}
+	addr := startServer(t, emails)
+
+	auth := smtp.PlainAuth("", "relay", "secret", "127.0.0.1")
+	recipients := []string{"alice@example.com", "bob@example.com", "hidden@example.com"}
+	if err := smtp.SendMail(addr, auth, "bounce@example.com", recipients, []byte(multipartMessage)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(emails.requests) != 1 {
+		t.Fatalf("expected 1 email sent, got %d", len(emails.requests))
+	}
+
+	expected := unsend.SendEmailRequest{
+		To:      []string{"alice@example.com"},
+		From:    `"App" <app@example.com>`,
+		Subject: "Café order",
+		ReplyTo: []string{"support@example.com"},
+		Cc:      []string{"bob@example.com"},
+		Bcc:     []string{"hidden@example.com"},
+		Text:    "Hello",
+		Html:    "<p>Hello=</p>",
+		Attachments: []unsend.Attachments{
+			{Filename: "report.csv", Content: base64.StdEncoding.EncodeToString([]byte("a,b\n1,2\n"))},
+		},
+	}
+	if !reflect.DeepEqual(emails.requests[0], expected) {
+		t.Errorf("expected request %+v, got %+v", expected, emails.requests[0])
+	}
+}
+
+func TestRelayAcceptsNullReversePath(t *testing.T) {
+	emails := &fakeEmails{}
+	addr := startServer(t, emails)
+
+	auth := smtp.PlainAuth("", "relay", "secret", "127.0.0.1")
+	if err := smtp.SendMail(addr, auth, "", []string{"alice@example.com"}, []byte(multipartMessage)); err != nil {
+		t.Fatalf("expected MAIL FROM:<> to be accepted, got %v", err)
+	}
+	if len(emails.requests) != 1 || emails.requests[0].From != `"App" <app@example.com>` {
+		t.Errorf("expected the From header to be the sender, got %+v", emails.requests)
+	}
+
+	err := smtp.SendMail(addr, auth, "", []string{"alice@example.com"}, []byte("Subject: hi\r\n\r\nhi\r\n"))
+	var smtpErr *textproto.Error
+	if !errors.As(err, &smtpErr) || smtpErr.Code != 554 {
+		t.Errorf("expected 554 without any sender, got %v", err)
+	}
+}
+
+func TestRelayLineLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		commands []string
+		expected []int
+	}{
+		{
+			name:     "Long command",
+			commands: []string{"EHLO " + strings.Repeat("a", 600)},
+			expected: []int{500},
+		},
+		{
+			name: "Longest text line",
+			commands: []string{
+				"HELO localhost",
+				"AUTH PLAIN " + base64.StdEncoding.EncodeToString([]byte("\x00relay\x00secret")),
+				"MAIL FROM:<app@example.com>",
+				"RCPT TO:<alice@example.com>",
+				"DATA",
+				"From: app@example.com\r\n\r\n" + strings.Repeat("a", 998) + "\r\n.",
+			},
+			expected: []int{250, 235, 250, 250, 354, 250},
+		},
+		{
+			name: "Long text line",
+			commands: []string{
+				"HELO localhost",
+				"AUTH PLAIN " + base64.StdEncoding.EncodeToString([]byte("\x00relay\x00secret")),
+				"MAIL FROM:<app@example.com>",
+				"RCPT TO:<alice@example.com>",
+				"DATA",
+				"From: app@example.com\r\n\r\n" + strings.Repeat("a", 999) + "\r\n.",
+			},
+			expected: []int{250, 235, 250, 250, 354, 500},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, err := textproto.Dial("tcp", startServer(t, &fakeEmails{}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+
+			if _, _, err := conn.ReadResponse(220); err != nil {
+				t.Fatal(err)
+			}
+			for i, command := range tt.commands {
+				conn.PrintfLine("%s", command)
+				code, message, _ := conn.ReadResponse(0)
+				if code != tt.expected[i] {
+					t.Fatalf("expected %d after %.20q, got %d %s", tt.expected[i], command, code, message)
+				}
+			}
+		})
+	}
+}
+
+func TestRelayRequiresAuth(t *testing.T) {
+	addr := startServer(t, &fakeEmails{})
+
+	err := smtp.SendMail(addr, nil, "app@example.com", []string{"alice@example.com"}, []byte("Subject: hi\r\n\r\nhi\r\n"))
+	if err == nil || !strings.HasPrefix(err.Error(), "530") {
+		t.Errorf("expected 530 without AUTH, got %v", err)
+	}
+
+	auth := smtp.PlainAuth("", "relay", "wrong", "127.0.0.1")
+	err = smtp.SendMail(addr, auth, "app@example.com", []string{"alice@example.com"}, []byte("Subject: hi\r\n\r\nhi\r\n"))
+	if err == nil || !strings.HasPrefix(err.Error(), "535") {
+		t.Errorf("expected 535 with a wrong password, got %v", err)
+	}
+}
+
+func TestRelayMapsApiErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedCode   int
+		expectedStatus string
+	}{
+		{
+			name:           "Rate limited",
+			err:            &unsend.APIError{StatusCode: http.StatusTooManyRequests},
+			expectedCode:   451,
+			expectedStatus: "4.7.0",
+		},
+		{
+			name:           "Server error",
+			err:            &unsend.APIError{StatusCode: http.StatusBadGateway},
+			expectedCode:   451,
+			expectedStatus: "4.3.0",
+		},
+		{
+			name:           "Rejected",
+			err:            &unsend.APIError{StatusCode: http.StatusBadRequest, Body: `{"error": "bad"}`},
+			expectedCode:   554,
+			expectedStatus: "5.6.0",
+		},
+		{
+			name:           "Unverified sender",
+			err:            &unsend.SenderDomainError{Domain: "example.com"},
+			expectedCode:   553,
+			expectedStatus: "5.7.1",
+		},
+		{
+			name:           "Network",
+			err:            io.ErrUnexpectedEOF,
+			expectedCode:   451,
+			expectedStatus: "4.4.1",
+		},
+		{
+			name:           "Send timeout",
+			err:            fmt.Errorf("request failed: %w", context.DeadlineExceeded),
+			expectedCode:   451,
+			expectedStatus: "4.4.1",
+		},
+		{
+			name:           "Campaign restricted",
+			err:            fmt.Errorf("request failed: %w", unsend.ErrCampaignSendRestricted),
+			expectedCode:   554,
+			expectedStatus: "5.6.0",
+		},
+		{
+			name:           "Unsupported by server",
+			err:            unsend.ErrUnsupportedByServer,
+			expectedCode:   554,
+			expectedStatus: "5.6.0",
+		},
+		{
+			name:           "Idempotency key reused",
+			err:            fmt.Errorf("request failed: %w", unsend.ErrIdempotencyKeyReused),
+			expectedCode:   554,
+			expectedStatus: "5.6.0",
+		},
+		{
+			name:           "Invalid request",
+			err:            fmt.Errorf("[ERROR]: SendEmailRequest not valid; %w", &unsend.ValidationError{Errors: []string{"'From' is required"}}),
+			expectedCode:   554,
+			expectedStatus: "5.6.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := startServer(t, &fakeEmails{err: tt.err})
+
+			auth := smtp.PlainAuth("", "relay", "secret", "127.0.0.1")
+			err := smtp.SendMail(addr, auth, "app@example.com", []string{"alice@example.com"}, []byte("Subject: hi\r\n\r\nhi\r\n"))
+			var smtpErr *textproto.Error
+			if !errors.As(err, &smtpErr) || smtpErr.Code != tt.expectedCode || !strings.HasPrefix(smtpErr.Msg, tt.expectedStatus) {
+				t.Errorf("expected %d %s, got %v", tt.expectedCode, tt.expectedStatus, err)
+			}
+		})
+	}
+}
+
+func TestApplyEnvelope(t *testing.T) {
+	tests := []struct {
+		name       string
+		to         []string
+		cc         []string
+		recipients []string
+		expected   unsend.SendEmailRequest
+	}{
+		{
+			name:       "Headers match the envelope",
+			to:         []string{"Alice <alice@example.com>"},
+			cc:         []string{"bob@example.com"},
+			recipients: []string{"ALICE@example.com", "bob@example.com"},
+			expected:   unsend.SendEmailRequest{To: []string{"Alice <alice@example.com>"}, Cc: []string{"bob@example.com"}},
+		},
+		{
+			name:       "Split Bcc transaction",
+			to:         []string{"alice@example.com"},
+			cc:         []string{"bob@example.com"},
+			recipients: []string{"hidden@example.com"},
+			expected:   unsend.SendEmailRequest{To: []string{"hidden@example.com"}},
+		},
+		{
+			name:       "Header recipients not in the envelope are dropped",
+			to:         []string{"alice@example.com", "carol@example.com"},
+			cc:         []string{"bob@example.com"},
+			recipients: []string{"alice@example.com", "hidden@example.com"},
+			expected:   unsend.SendEmailRequest{To: []string{"alice@example.com"}, Bcc: []string{"hidden@example.com"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &unsend.SendEmailRequest{From: "app@example.com", To: tt.to, Cc: tt.cc}
+			applyEnvelope(request, "bounce@example.com", tt.recipients)
+
+			tt.expected.From = "app@example.com"
+			if !reflect.DeepEqual(*request, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, *request)
+			}
+		})
+	}
+}
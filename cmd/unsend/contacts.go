@@ -0,0 +1,451 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+var contactCommands = []command{
+	{name: "get", summary: "get a contact", run: getContact},
+	{name: "create", summary: "create a contact", run: createContact},
+	{name: "upsert", summary: "create or replace a contact", run: upsertContact},
+	{name: "update", summary: "update a contact", run: updateContact},
+	{name: "delete", summary: "delete a contact", run: deleteContact},
+	{name: "import", summary: "create contacts from CSV or JSON", run: importContacts},
+	{name: "export", summary: "write contacts as CSV or JSON", run: exportContacts},
+}
+
+// contactFlags are the fields shared by create, upsert and update.
+type contactFlags struct {
+	input      *string
+	book       *string
+	id         *string
+	email      *string
+	firstName  *string
+	lastName   *string
+	subscribed *bool
+	properties stringList
+}
+
+func newContactFlags(flags *flag.FlagSet, withId bool) *contactFlags {
+	f := &contactFlags{
+		input:      flags.String("input", "", "JSON request file, or - for stdin"),
+		book:       flags.String("book", "", "contact book id"),
+		email:      flags.String("email", "", "email address"),
+		firstName:  flags.String("first-name", "", "first name"),
+		lastName:   flags.String("last-name", "", "last name"),
+		subscribed: flags.Bool("subscribed", false, "whether the contact is subscribed"),
+	}
+	if withId {
+		f.id = flags.String("id", "", "contact id")
+	}
+	flags.Var(&f.properties, "property", "custom property as key=value (repeatable)")
+	return f
+}
+
+func (f *contactFlags) apply(flags *flag.FlagSet, body *contactBody) error {
+	setString(flags, "email", &body.Email, *f.email)
+	setString(flags, "first-name", &body.FirstName, *f.firstName)
+	setString(flags, "last-name", &body.LastName, *f.lastName)
+	if flagSet(flags, "subscribed") {
+		body.Subscribed = f.subscribed
+	}
+
+	for _, property := range f.properties {
+		key, value, ok := strings.Cut(property, "=")
+		if !ok {
+			return fmt.Errorf("property %q is not in key=value form", property)
+		}
+		if body.Properties == nil {
+			body.Properties = map[string]interface{}{}
+		}
+		body.Properties[key] = value
+	}
+	return nil
+}
+
+// contactBody is what the CLI sends to create, replace or update a contact.
+// The SDK's requests always send subscribed, so a contact created or updated
+// without -subscribed would be unsubscribed; here it is left out unless the
+// flag, the -input file or the CSV cell sets it.
+type contactBody struct {
+	Email      string                 `json:"email,omitempty"`
+	FirstName  string                 `json:"firstName,omitempty"`
+	LastName   string                 `json:"lastName,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+	Subscribed *bool                  `json:"subscribed,omitempty"`
+}
+
+// sendContact validates body as the SDK's request for method and sends it
+// through the client, so the client's options still apply.
+func sendContact(ctx context.Context, a *app, method string, book string, id string, body contactBody) (*unsend.ContactIdResponse, error) {
+	if book == "" {
+		book = a.client.DefaultContactBookId
+	}
+
+	var name string
+	var invalid *unsend.ValidationError
+	switch method {
+	case http.MethodPost:
+		name = "CreateContactRequest"
+		invalid = unsend.CreateContactRequest{ContactBookId: book, Email: body.Email}.Validate()
+	case http.MethodPut:
+		name = "UpsertContactRequest"
+		invalid = unsend.UpsertContactRequest{ContactBookId: book, ContactId: id, Email: body.Email}.Validate()
+	default:
+		name = "UpdateContactRequest"
+		invalid = unsend.UpdateContactRequest{ContactBookId: book, ContactId: id}.Validate()
+	}
+	if invalid != nil {
+		return nil, fmt.Errorf("[ERROR]: %s not valid; %w", name, invalid)
+	}
+
+	req, err := a.client.NewRequestWithContext(ctx, method, "api/v1/contactBooks/"+book+"/contacts/"+id, body)
+	if err != nil {
+		return nil, err
+	}
+
+	response := new(unsend.ContactIdResponse)
+	if err := a.client.Execute(req, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func getContact(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet(a, "contacts get")
+	book := flags.String("book", "", "contact book id")
+	id := flags.String("id", "", "contact id")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	response, err := a.client.Contacts.GetContact(ctx, unsend.GetContactRequest{
+		ContactBookId: *book,
+		ContactId:     *id,
+	})
+	if err != nil {
+		return err
+	}
+	return a.print(response)
+}
+
+func createContact(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet(a, "contacts create")
+	f := newContactFlags(flags, false)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	body := contactBody{}
+	if err := readInput(a, *f.input, &body); err != nil {
+		return err
+	}
+	if err := f.apply(flags, &body); err != nil {
+		return err
+	}
+
+	response, err := sendContact(ctx, a, http.MethodPost, *f.book, "", body)
+	if err != nil {
+		return err
+	}
+	return a.print(response)
+}
+
+func upsertContact(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet(a, "contacts upsert")
+	f := newContactFlags(flags, true)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	body := contactBody{}
+	if err := readInput(a, *f.input, &body); err != nil {
+		return err
+	}
+	if err := f.apply(flags, &body); err != nil {
+		return err
+	}
+
+	response, err := sendContact(ctx, a, http.MethodPut, *f.book, *f.id, body)
+	if err != nil {
+		return err
+	}
+	return a.print(response)
+}
+
+func updateContact(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet(a, "contacts update")
+	f := newContactFlags(flags, true)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	body := contactBody{}
+	if err := readInput(a, *f.input, &body); err != nil {
+		return err
+	}
+	if err := f.apply(flags, &body); err != nil {
+		return err
+	}
+	// The API doesn't change a contact's email in an update.
+	body.Email = ""
+
+	response, err := sendContact(ctx, a, http.MethodPatch, *f.book, *f.id, body)
+	if err != nil {
+		return err
+	}
+	return a.print(response)
+}
+
+func deleteContact(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet(a, "contacts delete")
+	book := flags.String("book", "", "contact book id")
+	id := flags.String("id", "", "contact id")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	response, err := a.client.Contacts.DeleteContact(ctx, unsend.DeleteContactRequest{
+		ContactBookId: *book,
+		ContactId:     *id,
+	})
+	if err != nil {
+		return err
+	}
+	return a.print(response)
+}
+
+type importResult struct {
+	Row       int    `json:"row"`
+	Email     string `json:"email"`
+	ContactId string `json:"contactId,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+func importContacts(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet(a, "contacts import")
+	book := flags.String("book", "", "contact book id")
+	input := flags.String("input", "-", "CSV or JSON file, or - for stdin")
+	format := flags.String("format", "csv", "input format: csv or json")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *book == "" {
+		*book = a.client.DefaultContactBookId
+	}
+	if err := requireFlag(flags, "book", *book); err != nil {
+		return err
+	}
+
+	r, closeInput, err := openInput(a, *input)
+	if err != nil {
+		return err
+	}
+	defer closeInput()
+
+	var requests []contactBody
+	switch *format {
+	case "csv":
+		requests, err = readContactsCSV(r)
+	case "json":
+		err = json.NewDecoder(r).Decode(&requests)
+	default:
+		fmt.Fprintf(flags.Output(), "unknown format %q\n", *format)
+		return errUsage
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read contacts: %w", err)
+	}
+
+	results := make([]importResult, 0, len(requests))
+	failed := 0
+	for i, request := range requests {
+		result := importResult{Row: i + 1, Email: request.Email}
+
+		response, err := sendContact(ctx, a, http.MethodPost, *book, "", request)
+		if err != nil {
+			result.Error = err.Error()
+			failed++
+		} else {
+			result.ContactId = response.ContactId
+		}
+		results = append(results, result)
+	}
+
+	if err := a.print(results); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d contacts failed to import", failed, len(requests))
+	}
+	return nil
+}
+
+// readContactsCSV reads rows with a header line. The email, firstName,
+// lastName and subscribed columns map to fields; any other column becomes a
+// property.
+func readContactsCSV(r io.Reader) ([]contactBody, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	var requests []contactBody
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return requests, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		request := contactBody{}
+		for i, column := range header {
+			value := row[i]
+			switch column {
+			case "email":
+				request.Email = value
+			case "firstName":
+				request.FirstName = value
+			case "lastName":
+				request.LastName = value
+			case "subscribed":
+				if value == "" {
+					continue
+				}
+				subscribed, err := strconv.ParseBool(value)
+				if err != nil {
+					return nil, fmt.Errorf("row %d: subscribed: %w", len(requests)+1, err)
+				}
+				request.Subscribed = &subscribed
+			default:
+				if value == "" {
+					continue
+				}
+				if request.Properties == nil {
+					request.Properties = map[string]interface{}{}
+				}
+				request.Properties[column] = value
+			}
+		}
+		requests = append(requests, request)
+	}
+}
+
+func exportContacts(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet(a, "contacts export")
+	book := flags.String("book", "", "contact book id")
+	var ids stringList
+	flags.Var(&ids, "id", "contact id to export (repeatable); read one per line from -input when omitted")
+	input := flags.String("input", "-", "file of contact ids, or - for stdin")
+	format := flags.String("format", "json", "export format: csv or json")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *book == "" {
+		*book = a.client.DefaultContactBookId
+	}
+	if err := requireFlag(flags, "book", *book); err != nil {
+		return err
+	}
+	if *format != "csv" && *format != "json" {
+		fmt.Fprintf(flags.Output(), "unknown format %q\n", *format)
+		return errUsage
+	}
+
+	if len(ids) == 0 {
+		r, closeInput, err := openInput(a, *input)
+		if err != nil {
+			return err
+		}
+		defer closeInput()
+
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			if id := strings.TrimSpace(scanner.Text()); id != "" {
+				ids = append(ids, id)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+	}
+
+	contacts := make([]unsend.GetContactResponse, 0, len(ids))
+	for _, id := range ids {
+		contact, err := a.client.Contacts.GetContact(ctx, unsend.GetContactRequest{
+			ContactBookId: *book,
+			ContactId:     id,
+		})
+		if err != nil {
+			return fmt.Errorf("contact %s: %w", id, err)
+		}
+		contacts = append(contacts, *contact)
+	}
+
+	if *format == "csv" {
+		return writeContactsCSV(a.stdout, contacts)
+	}
+	return a.print(contacts)
+}
+
+func writeContactsCSV(w io.Writer, contacts []unsend.GetContactResponse) error {
+	propertySet := map[string]bool{}
+	for _, contact := range contacts {
+		for key := range contact.Properties {
+			propertySet[key] = true
+		}
+	}
+	properties := make([]string, 0, len(propertySet))
+	for key := range propertySet {
+		properties = append(properties, key)
+	}
+	sort.Strings(properties)
+
+	writer := csv.NewWriter(w)
+	header := append([]string{"id", "email", "firstName", "lastName", "subscribed", "createdAt", "updatedAt"}, properties...)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, contact := range contacts {
+		row := []string{
+			contact.Id,
+			contact.Email,
+			contact.FirstName,
+			contact.LastName,
+			strconv.FormatBool(contact.Subscribed),
+			contact.CreatedAt,
+			contact.UpdatedAt,
+		}
+		for _, key := range properties {
+			value := ""
+			if v, ok := contact.Properties[key]; ok && v != nil {
+				value = fmt.Sprint(v)
+			}
+			row = append(row, value)
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"context"
+)
+
+var domainCommands = []command{
+	{name: "list", summary: "list domains", run: listDomains},
+}
+
+func listDomains(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet(a, "domains list")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	response, err := a.client.Domains.GetDomains(ctx)
+	if err != nil {
+		return err
+	}
+	return a.print(response)
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+var emailCommands = []command{
+	{name: "send", summary: "send an email", run: sendEmail},
+	{name: "get", summary: "get an email by id", run: getEmail},
+	{name: "reschedule", summary: "change when a scheduled email is sent", run: rescheduleEmail},
+	{name: "cancel", summary: "cancel a scheduled email", run: cancelEmail},
+	{name: "export", summary: "write an email as an .eml message", run: exportEmail},
+}
+
+func sendEmail(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet(a, "emails send")
+	input := flags.String("input", "", "JSON SendEmailRequest file, or - for stdin")
+	eml := flags.String("eml", "", ".eml message file to send, or - for stdin")
+	var to, cc, bcc, replyTo, attachments stringList
+	flags.Var(&to, "to", "recipient address (repeatable)")
+	flags.Var(&cc, "cc", "cc address (repeatable)")
+	flags.Var(&bcc, "bcc", "bcc address (repeatable)")
+	flags.Var(&replyTo, "reply-to", "reply-to address (repeatable)")
+	flags.Var(&attachments, "attach", "file to attach (repeatable)")
+	from := flags.String("from", "", "sender address")
+	subject := flags.String("subject", "", "subject line")
+	text := flags.String("text", "", "plain text body")
+	html := flags.String("html", "", "HTML body")
+	templateId := flags.String("template-id", "", "template to render")
+	scheduledAt := flags.String("scheduled-at", "", "RFC 3339 time to send at")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if *input != "" && *eml != "" {
+		fmt.Fprintln(flags.Output(), "-input and -eml can't be used together")
+		return errUsage
+	}
+
+	request := unsend.SendEmailRequest{}
+	if err := readInput(a, *input, &request); err != nil {
+		return err
+	}
+	if *eml != "" {
+		r, closeInput, err := openInput(a, *eml)
+		if err != nil {
+			return err
+		}
+		defer closeInput()
+
+		parsed, err := unsend.ParseMessage(r)
+		if err != nil {
+			return err
+		}
+		request = *parsed
+	}
+
+	if len(to) > 0 {
+		request.To = to
+	}
+	if len(cc) > 0 {
+		request.Cc = cc
+	}
+	if len(bcc) > 0 {
+		request.Bcc = bcc
+	}
+	if len(replyTo) > 0 {
+		request.ReplyTo = replyTo
+	}
+	setString(flags, "from", &request.From, *from)
+	setString(flags, "subject", &request.Subject, *subject)
+	setString(flags, "text", &request.Text, *text)
+	setString(flags, "html", &request.Html, *html)
+	setString(flags, "template-id", &request.TemplateId, *templateId)
+	setString(flags, "scheduled-at", &request.ScheduledAt, *scheduledAt)
+
+	for _, path := range attachments {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		request.Attachments = append(request.Attachments, unsend.Attachments{
+			Filename: filepath.Base(path),
+			Content:  base64.StdEncoding.EncodeToString(content),
+		})
+	}
+
+	response, err := a.client.Emails.SendEmail(ctx, request)
+	if err != nil {
+		return err
+	}
+	return a.print(response)
+}
+
+func getEmail(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet(a, "emails get")
+	id := flags.String("id", "", "email id")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if err := requireFlag(flags, "id", *id); err != nil {
+		return err
+	}
+
+	response, err := a.client.Emails.GetEmail(ctx, unsend.GetEmailRequest{EmailId: *id})
+	if err != nil {
+		return err
+	}
+	return a.print(response)
+}
+
+func rescheduleEmail(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet(a, "emails reschedule")
+	id := flags.String("id", "", "email id")
+	scheduledAt := flags.String("scheduled-at", "", "RFC 3339 time to send at")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if err := requireFlag(flags, "id", *id); err != nil {
+		return err
+	}
+
+	response, err := a.client.Emails.UpdateSchedule(ctx, unsend.UpdateScheduleRequest{
+		EmailId:     *id,
+		ScheduledAt: *scheduledAt,
+	})
+	if err != nil {
+		return err
+	}
+	return a.print(response)
+}
+
+func cancelEmail(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet(a, "emails cancel")
+	id := flags.String("id", "", "email id")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if err := requireFlag(flags, "id", *id); err != nil {
+		return err
+	}
+
+	response, err := a.client.Emails.CancelSchedule(ctx, unsend.CancelScheduleRequest{EmailId: *id})
+	if err != nil {
+		return err
+	}
+	return a.print(response)
+}
+
+func exportEmail(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet(a, "emails export")
+	id := flags.String("id", "", "email id")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if err := requireFlag(flags, "id", *id); err != nil {
+		return err
+	}
+
+	response, err := a.client.Emails.GetEmail(ctx, unsend.GetEmailRequest{EmailId: *id})
+	if err != nil {
+		return err
+	}
+	return response.WriteMessage(a.stdout)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func openInput(a *app, path string) (io.Reader, func(), error) {
+	if path == "-" {
+		return a.stdin, func() {}, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, func() { f.Close() }, nil
+}
+
+// readInput decodes JSON from path, or stdin when path is "-", into v. An
+// empty path leaves v untouched.
+func readInput(a *app, path string, v interface{}) error {
+	if path == "" {
+		return nil
+	}
+
+	r, closeInput, err := openInput(a, path)
+	if err != nil {
+		return err
+	}
+	defer closeInput()
+
+	if err := json.NewDecoder(r).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode input: %w", err)
+	}
+	return nil
+}
+
+// setString overwrites dst with value only when the flag was given, so flags
+// take precedence over -input without blanking fields it provided.
+func setString(flags *flag.FlagSet, name string, dst *string, value string) {
+	if flagSet(flags, name) {
+		*dst = value
+	}
+}
@@ -0,0 +1,196 @@
+// Command unsend is a command line client for the Unsend API.
+//
+//	unsend [global flags] <emails|contacts|domains> <command> [flags]
+//
+// Requests can be built from flags, from JSON passed with -input (use "-" for
+// stdin), or both, in which case flags win. Results are written as JSON or as
+// a table, selected with -output.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+var errUsage = errors.New("usage")
+
+type app struct {
+	client *unsend.Client
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	output string
+}
+
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, a *app, args []string) error
+}
+
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("unsend", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	apiKey := flags.String("api-key", "", "API key (default $"+unsend.ENV_KEY_API_KEY+")")
+	baseUrl := flags.String("base-url", "", "API base URL including /api (default $"+unsend.ENV_KEY_BASE_URL+")")
+	profile := flags.String("profile", "", "config file profile (default $"+unsend.ENV_KEY_PROFILE+")")
+	output := flags.String("output", "json", "output format: json or table")
+	flags.Usage = func() { printUsage(stderr, flags) }
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *output != "json" && *output != "table" {
+		fmt.Fprintf(stderr, "unknown output format %q\n", *output)
+		return exitUsage
+	}
+
+	rest := flags.Args()
+	if len(rest) < 2 {
+		printUsage(stderr, flags)
+		return exitUsage
+	}
+
+	cmd, ok := findCommand(rest[0], rest[1])
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n", strings.Join(rest[:2], " "))
+		printUsage(stderr, flags)
+		return exitUsage
+	}
+
+	var opts []unsend.ClientOption
+	if *apiKey != "" {
+		opts = append(opts, unsend.WithApiKey(*apiKey))
+	}
+	if *baseUrl != "" {
+		opts = append(opts, unsend.WithBaseUrl(*baseUrl))
+	}
+	if *profile != "" {
+		opts = append(opts, unsend.WithProfile(*profile))
+	}
+
+	client, err := unsend.NewClient(opts...)
+	if err != nil {
+		fmt.Fprintf(stderr, "[ERROR] - %s\n", err.Error())
+		return exitError
+	}
+
+	a := &app{
+		client: client,
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+		output: *output,
+	}
+
+	if err := cmd.run(ctx, a, rest[2:]); err != nil {
+		if errors.Is(err, errUsage) || errors.Is(err, flag.ErrHelp) {
+			return exitUsage
+		}
+		fmt.Fprintf(stderr, "[ERROR] - %s\n", err.Error())
+		return exitError
+	}
+
+	return exitOK
+}
+
+func commands() map[string][]command {
+	return map[string][]command{
+		"emails":   emailCommands,
+		"contacts": contactCommands,
+		"domains":  domainCommands,
+	}
+}
+
+func findCommand(service string, name string) (command, bool) {
+	for _, cmd := range commands()[service] {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+func printUsage(w io.Writer, flags *flag.FlagSet) {
+	fmt.Fprintln(w, "Usage: unsend [global flags] <service> <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Global flags:")
+	flags.SetOutput(w)
+	flags.PrintDefaults()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, service := range []string{"emails", "contacts", "domains"} {
+		for _, cmd := range commands()[service] {
+			fmt.Fprintf(w, "  %s %-12s %s\n", service, cmd.name, cmd.summary)
+		}
+	}
+}
+
+func newFlagSet(a *app, name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(a.stderr)
+	return flags
+}
+
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintf(flags.Output(), "unexpected arguments: %s\n", strings.Join(flags.Args(), " "))
+		return errUsage
+	}
+	return nil
+}
+
+func requireFlag(flags *flag.FlagSet, name string, value string) error {
+	if value == "" {
+		fmt.Fprintf(flags.Output(), "-%s is required\n", name)
+		return errUsage
+	}
+	return nil
+}
+
+// stringList is a flag that can be repeated or given a comma separated list.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+// flagSet reports whether the named flag was given on the command line.
+func flagSet(flags *flag.FlagSet, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+func newTestServer(t *testing.T, bodies *[]map[string]interface{}) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-api-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": "unauthorized"}`))
+			return
+		}
+
+		if bodies != nil && r.Body != nil {
+			body, _ := io.ReadAll(r.Body)
+			decoded := map[string]interface{}{}
+			if len(body) > 0 {
+				json.Unmarshal(body, &decoded)
+			}
+			*bodies = append(*bodies, decoded)
+		}
+
+		switch {
+		case r.URL.Path == "/api/v1/emails" && r.Method == http.MethodPost:
+			w.Write([]byte(`{"emailId": "email123"}`))
+		case r.URL.Path == "/api/v1/domains" && r.Method == http.MethodGet:
+			w.Write([]byte(`[{"id": 1, "name": "unsend.dev", "status": "SUCCESS", "region": "us-east-1"}]`))
+		case r.URL.Path == "/api/v1/contactBooks/book123/contacts/" && r.Method == http.MethodPost:
+			w.Write([]byte(`{"contactId": "contact123"}`))
+		case strings.HasPrefix(r.URL.Path, "/api/v1/contactBooks/book123/contacts/") && r.Method == http.MethodPatch:
+			w.Write([]byte(`{"contactId": "contact123"}`))
+		case strings.HasPrefix(r.URL.Path, "/api/v1/contactBooks/book123/contacts/") && r.Method == http.MethodGet:
+			id := strings.TrimPrefix(r.URL.Path, "/api/v1/contactBooks/book123/contacts/")
+			w.Write([]byte(`{"id": "` + id + `", "email": "` + id + `@example.com", "subscribed": true, "properties": {"plan": "pro"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "not found"}`))
+		}
+	}))
+}
+
+func runCLI(t *testing.T, server *httptest.Server, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	args = append([]string{"-api-key", "test-api-key", "-base-url", server.URL + "/api"}, args...)
+	code := run(context.Background(), args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestSendEmailFromStdinWithFlagOverrides(t *testing.T) {
+	var bodies []map[string]interface{}
+	server := newTestServer(t, &bodies)
+	defer server.Close()
+
+	stdin := `{"to": ["a@b.c"], "from": "hello@unsend.dev", "subject": "From stdin", "text": "Hello"}`
+	code, stdout, stderr := runCLI(t, server, stdin, "emails", "send", "-input", "-", "-subject", "From flags")
+	if code != exitOK {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr)
+	}
+
+	if !strings.Contains(stdout, `"emailId": "email123"`) {
+		t.Errorf("expected email ID in output, got %s", stdout)
+	}
+	if len(bodies) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(bodies))
+	}
+	if bodies[0]["subject"] != "From flags" || bodies[0]["text"] != "Hello" {
+		t.Errorf("expected flags to override stdin fields, got %v", bodies[0])
+	}
+}
+
+func TestSendEmailFromEml(t *testing.T) {
+	var bodies []map[string]interface{}
+	server := newTestServer(t, &bodies)
+	defer server.Close()
+
+	stdin := "From: hello@unsend.dev\r\nTo: a@b.c\r\nSubject: From eml\r\n\r\nHello\r\n"
+	code, _, stderr := runCLI(t, server, stdin, "emails", "send", "-eml", "-", "-to", "override@b.c")
+	if code != exitOK {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr)
+	}
+
+	if len(bodies) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(bodies))
+	}
+	to, _ := bodies[0]["to"].([]interface{})
+	if bodies[0]["subject"] != "From eml" || bodies[0]["text"] != "Hello\n" || len(to) != 1 || to[0] != "override@b.c" {
+		t.Errorf("expected request from the eml with flags applied, got %v", bodies[0])
+	}
+}
+
+func TestListDomainsTable(t *testing.T) {
+	server := newTestServer(t, nil)
+	defer server.Close()
+
+	code, stdout, stderr := runCLI(t, server, "", "-output", "table", "domains", "list")
+	if code != exitOK {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr)
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected header and 1 row, got %q", stdout)
+	}
+	if !strings.HasPrefix(lines[0], "ID") || !strings.Contains(lines[0], "NAME") {
+		t.Errorf("expected column headings, got %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "unsend.dev") || !strings.Contains(lines[1], "SUCCESS") {
+		t.Errorf("expected domain row, got %q", lines[1])
+	}
+}
+
+func TestImportContactsCSV(t *testing.T) {
+	var bodies []map[string]interface{}
+	server := newTestServer(t, &bodies)
+	defer server.Close()
+
+	stdin := "email,firstName,subscribed,plan\njane@example.com,Jane,true,pro\njohn@example.com,John,false,\nmax@example.com,Max,,\n"
+	code, stdout, stderr := runCLI(t, server, stdin, "contacts", "import", "-book", "book123")
+	if code != exitOK {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr)
+	}
+
+	if len(bodies) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(bodies))
+	}
+	if bodies[0]["email"] != "jane@example.com" || bodies[0]["subscribed"] != true {
+		t.Errorf("unexpected first contact %v", bodies[0])
+	}
+	if properties, _ := bodies[0]["properties"].(map[string]interface{}); properties["plan"] != "pro" {
+		t.Errorf("expected extra columns to become properties, got %v", bodies[0]["properties"])
+	}
+	if bodies[1]["subscribed"] != false {
+		t.Errorf("expected subscribed to be sent as false, got %v", bodies[1]["subscribed"])
+	}
+	if _, ok := bodies[2]["subscribed"]; ok || bodies[2]["email"] != "max@example.com" {
+		t.Errorf("expected an empty subscribed cell to be left unset, got %v", bodies[2])
+	}
+	if strings.Count(stdout, "contact123") != 3 {
+		t.Errorf("expected every contact ID in output, got %s", stdout)
+	}
+}
+
+func TestContactSubscribed(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected interface{}
+	}{
+		{"Create left alone", []string{"create", "-email", "jane@example.com"}, nil},
+		{"Create subscribed", []string{"create", "-email", "jane@example.com", "-subscribed"}, true},
+		{"Update left alone", []string{"update", "-id", "contact123", "-first-name", "Jane"}, nil},
+		{"Update unsubscribed", []string{"update", "-id", "contact123", "-subscribed=false"}, false},
+		{"Update subscribed", []string{"update", "-id", "contact123", "-subscribed"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var bodies []map[string]interface{}
+			server := newTestServer(t, &bodies)
+			defer server.Close()
+
+			args := append([]string{"contacts", tt.args[0], "-book", "book123"}, tt.args[1:]...)
+			code, _, stderr := runCLI(t, server, "", args...)
+			if code != exitOK {
+				t.Fatalf("expected exit code 0, got %d: %s", code, stderr)
+			}
+			if len(bodies) != 1 {
+				t.Fatalf("expected 1 request, got %d", len(bodies))
+			}
+			subscribed, ok := bodies[0]["subscribed"]
+			if ok != (tt.expected != nil) || subscribed != tt.expected {
+				t.Errorf("expected subscribed %v, got %v", tt.expected, bodies[0])
+			}
+		})
+	}
+}
+
+func TestExportContactsCSV(t *testing.T) {
+	server := newTestServer(t, nil)
+	defer server.Close()
+
+	code, stdout, stderr := runCLI(t, server, "c1\nc2\n", "contacts", "export", "-book", "book123", "-format", "csv")
+	if code != exitOK {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr)
+	}
+
+	expected := "id,email,firstName,lastName,subscribed,createdAt,updatedAt,plan\n" +
+		"c1,c1@example.com,,,true,,,pro\n" +
+		"c2,c2@example.com,,,true,,,pro\n"
+	if stdout != expected {
+		t.Errorf("expected output %q, got %q", expected, stdout)
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	server := newTestServer(t, nil)
+	defer server.Close()
+	t.Setenv(unsend.ENV_KEY_CONFIG_FILE, filepath.Join(t.TempDir(), "missing.toml"))
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "Missing command", args: []string{"emails"}},
+		{name: "Unknown command", args: []string{"emails", "forward"}},
+		{name: "Missing required flag", args: []string{"emails", "get"}},
+		{name: "Import without a contact book", args: []string{"contacts", "import"}},
+		{name: "Export without a contact book", args: []string{"contacts", "export", "-id", "c1"}},
+		{name: "Unknown output", args: []string{"-output", "xml", "domains", "list"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, _ := runCLI(t, server, "", tt.args...)
+			if code != exitUsage {
+				t.Errorf("expected exit code %d, got %d", exitUsage, code)
+			}
+		})
+	}
+}
+
+func TestAPIErrorExitCode(t *testing.T) {
+	server := newTestServer(t, nil)
+	defer server.Close()
+
+	code, _, stderr := runCLI(t, server, "", "emails", "get", "-id", "missing")
+	if code != exitError {
+		t.Fatalf("expected exit code %d, got %d", exitError, code)
+	}
+	if !strings.Contains(stderr, "404") {
+		t.Errorf("expected API error on stderr, got %s", stderr)
+	}
+}
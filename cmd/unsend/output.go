@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+)
+
+func (a *app) print(v interface{}) error {
+	if a.output == "table" {
+		return writeTable(a.stdout, v)
+	}
+
+	encoder := json.NewEncoder(a.stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// writeTable renders a struct as FIELD/VALUE rows and a slice of structs as
+// one row per element, using the JSON field names as headings.
+func writeTable(w io.Writer, v interface{}) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	switch rv.Kind() {
+	case reflect.Slice:
+		elemType := rv.Type().Elem()
+		for elemType.Kind() == reflect.Pointer {
+			elemType = elemType.Elem()
+		}
+		if elemType.Kind() != reflect.Struct {
+			for i := 0; i < rv.Len(); i++ {
+				fmt.Fprintln(tw, formatCell(rv.Index(i)))
+			}
+			break
+		}
+
+		fields := tableFields(elemType)
+		headings := make([]string, len(fields))
+		for i, field := range fields {
+			headings[i] = strings.ToUpper(field.heading)
+		}
+		fmt.Fprintln(tw, strings.Join(headings, "\t"))
+
+		for i := 0; i < rv.Len(); i++ {
+			elem := reflect.Indirect(rv.Index(i))
+			cells := make([]string, len(fields))
+			for j, field := range fields {
+				cells[j] = formatCell(elem.Field(field.index))
+			}
+			fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		}
+	case reflect.Struct:
+		fmt.Fprintln(tw, "FIELD\tVALUE")
+		for _, field := range tableFields(rv.Type()) {
+			fmt.Fprintf(tw, "%s\t%s\n", field.heading, formatCell(rv.Field(field.index)))
+		}
+	default:
+		fmt.Fprintln(tw, formatCell(rv))
+	}
+
+	return tw.Flush()
+}
+
+type tableField struct {
+	heading string
+	index   int
+}
+
+func tableFields(t reflect.Type) []tableField {
+	var fields []tableField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, tableField{heading: name, index: i})
+	}
+	return fields
+}
+
+func formatCell(v reflect.Value) string {
+	if !v.IsValid() {
+		return ""
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return ""
+		}
+		return formatCell(v.Elem())
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.String {
+			items := make([]string, v.Len())
+			for i := range items {
+				items[i] = v.Index(i).String()
+			}
+			return strings.Join(items, ",")
+		}
+		fallthrough
+	case reflect.Map, reflect.Struct:
+		if v.Kind() != reflect.Struct && v.Len() == 0 {
+			return ""
+		}
+		encoded, err := json.Marshal(v.Interface())
+		if err != nil {
+			return fmt.Sprint(v.Interface())
+		}
+		return string(encoded)
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
@@ -0,0 +1,341 @@
+package unsend
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DEFAULT_TIMEOUT = 30 * time.Second
+
+// Profile is a named set of connection settings in the config file.
+type Profile struct {
+	ApiKey               string
+	BaseUrl              string
+	Timeout              time.Duration
+	DefaultFrom          string
+	DefaultContactBookId string
+}
+
+// Config is the parsed config file. The file is a small subset of TOML:
+//
+//	default_profile = "cloud"
+//
+//	[profiles.cloud]
+//	api_key = "us_xxx"
+//
+//	[profiles.self-hosted]
+//	api_key = "us_yyy"
+//	base_url = "https://unsend.example.com/api"
+//	timeout = "10s"
+//	default_from = "hello@example.com"
+//	default_contact_book_id = "cm8ath8d20001s3p3if0mhoq7"
+type Config struct {
+	DefaultProfile string
+	Profiles       map[string]Profile
+}
+
+// DefaultConfigPath returns $UNSEND_CONFIG_FILE, or unsend/config.toml in the
+// user config directory (~/.config on Linux).
+func DefaultConfigPath() (string, error) {
+	if path, ok := os.LookupEnv(ENV_KEY_CONFIG_FILE); ok {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "unsend", "config.toml"), nil
+}
+
+func LoadConfig(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	config, err := ParseConfig(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return config, nil
+}
+
+func ParseConfig(r io.Reader) (*Config, error) {
+	config := &Config{Profiles: map[string]Profile{}}
+	section := ""
+
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(stripConfigComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			name, err := parseProfileHeader(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			if _, ok := config.Profiles[name]; ok {
+				return nil, fmt.Errorf("line %d: profile '%s' is defined twice", lineNumber, name)
+			}
+			config.Profiles[name] = Profile{}
+			section = name
+			continue
+		}
+
+		key, rawValue, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNumber)
+		}
+		key = strings.TrimSpace(key)
+		value, err := parseConfigValue(strings.TrimSpace(rawValue))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		if section == "" {
+			if key != "default_profile" {
+				return nil, fmt.Errorf("line %d: unknown key '%s'", lineNumber, key)
+			}
+			config.DefaultProfile = value
+			continue
+		}
+
+		profile := config.Profiles[section]
+		if err := profile.set(key, value); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		config.Profiles[section] = profile
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if config.DefaultProfile != "" {
+		if _, ok := config.Profiles[config.DefaultProfile]; !ok {
+			return nil, fmt.Errorf("default_profile '%s' is not defined", config.DefaultProfile)
+		}
+	}
+
+	return config, nil
+}
+
+func (p *Profile) set(key string, value string) error {
+	switch key {
+	case "api_key":
+		p.ApiKey = value
+	case "base_url":
+		p.BaseUrl = value
+	case "default_from":
+		p.DefaultFrom = value
+	case "default_contact_book_id":
+		p.DefaultContactBookId = value
+	case "timeout":
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			seconds, intErr := strconv.Atoi(value)
+			if intErr != nil {
+				return fmt.Errorf("timeout: %w", err)
+			}
+			timeout = time.Duration(seconds) * time.Second
+		}
+		p.Timeout = timeout
+	default:
+		return fmt.Errorf("unknown key '%s'", key)
+	}
+	return nil
+}
+
+func parseProfileHeader(line string) (string, error) {
+	if !strings.HasSuffix(line, "]") {
+		return "", errors.New("unterminated section header")
+	}
+
+	name, ok := strings.CutPrefix(strings.TrimSpace(line[1:len(line)-1]), "profiles.")
+	if !ok {
+		return "", fmt.Errorf("unknown section %s; expected [profiles.<name>]", line)
+	}
+	if strings.HasPrefix(name, `"`) {
+		unquoted, err := strconv.Unquote(name)
+		if err != nil {
+			return "", fmt.Errorf("invalid profile name %s", name)
+		}
+		name = unquoted
+	}
+	if name == "" {
+		return "", errors.New("profile name is empty")
+	}
+	return name, nil
+}
+
+// parseConfigValue accepts basic ("...") and literal ('...') strings, and bare
+// integers and booleans, returning them as strings.
+func parseConfigValue(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, `"`):
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid string %s", value)
+		}
+		return unquoted, nil
+	case strings.HasPrefix(value, "'"):
+		if len(value) < 2 || !strings.HasSuffix(value, "'") {
+			return "", fmt.Errorf("invalid string %s", value)
+		}
+		return value[1 : len(value)-1], nil
+	case value == "true" || value == "false":
+		return value, nil
+	default:
+		if _, err := strconv.Atoi(value); err != nil {
+			return "", fmt.Errorf("invalid value %s", value)
+		}
+		return value, nil
+	}
+}
+
+func stripConfigComment(line string) string {
+	quote := byte(0)
+	escaped := false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && c == '\\':
+			escaped = true
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == 0 && (c == '"' || c == '\''):
+			quote = c
+		case quote == 0 && c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// resolveSettings works out the connection settings for NewClient. Each field
+// is taken from the first source that sets it, in this order:
+//
+//  1. Options passed to NewClient (WithApiKey, WithBaseUrl, WithTimeout).
+//  2. A profile selected by name, with WithProfile or else UNSEND_PROFILE.
+//     Naming a profile is a deliberate choice, so it beats ambient env vars.
+//  3. The UNSEND_API_KEY and UNSEND_BASE_URL environment variables.
+//  4. The config file's default_profile.
+//  5. Built in defaults (DEFAULT_BASE_URL, DEFAULT_TIMEOUT).
+//
+// The base URL is the exception: it belongs to the API key, so it is only
+// taken from the source of the key or one before it, and otherwise defaults.
+// An UNSEND_API_KEY is never sent to the default profile's server.
+//
+// The config file is read from WithConfigFile, UNSEND_CONFIG_FILE or
+// DefaultConfigPath. A missing file is only an error when a profile is named,
+// and a file that can't be read or parsed only when a profile is named or the
+// file was chosen with WithConfigFile or UNSEND_CONFIG_FILE. A broken file at
+// the default path is otherwise ignored, so it can't stop a client that
+// doesn't use it.
+func resolveSettings(options *clientOptions) (Profile, error) {
+	profileName := options.profile
+	if profileName == "" {
+		profileName = os.Getenv(ENV_KEY_PROFILE)
+	}
+	_, envConfigFile := os.LookupEnv(ENV_KEY_CONFIG_FILE)
+	configRequired := profileName != "" || options.configFile != "" || envConfigFile
+
+	configPath := options.configFile
+	if configPath == "" {
+		var err error
+		configPath, err = DefaultConfigPath()
+		if err != nil && profileName != "" {
+			return Profile{}, err
+		}
+	}
+
+	var config *Config
+	if configPath != "" {
+		var err error
+		config, err = LoadConfig(configPath)
+		if errors.Is(err, fs.ErrNotExist) && profileName == "" {
+			config, err = nil, nil
+		} else if err != nil && !configRequired {
+			if options.logger != nil {
+				options.logger.Debug("ignoring unsend config file", "path", configPath, "error", err)
+			}
+			config, err = nil, nil
+		}
+		if err != nil {
+			return Profile{}, fmt.Errorf("failed to load config: %w", err)
+		}
+	}
+
+	var selected, fallback Profile
+	if profileName != "" {
+		if config == nil {
+			return Profile{}, fmt.Errorf("profile '%s' selected but no config file is set", profileName)
+		}
+		profile, ok := config.Profiles[profileName]
+		if !ok {
+			return Profile{}, fmt.Errorf("profile '%s' not found in %s", profileName, configPath)
+		}
+		selected = profile
+	} else if config != nil && config.DefaultProfile != "" {
+		fallback = config.Profiles[config.DefaultProfile]
+	}
+
+	env := Profile{
+		ApiKey:  os.Getenv(ENV_KEY_API_KEY),
+		BaseUrl: os.Getenv(ENV_KEY_BASE_URL),
+	}
+	explicit := Profile{
+		ApiKey:  options.apiKey,
+		BaseUrl: options.baseUrl,
+		Timeout: options.timeout,
+	}
+	defaults := Profile{
+		BaseUrl: DEFAULT_BASE_URL,
+		Timeout: DEFAULT_TIMEOUT,
+	}
+
+	layers := []Profile{explicit, selected, env, fallback, defaults}
+	settings := Profile{}
+	keyLayer := len(layers) - 1
+	for i, layer := range layers {
+		if settings.ApiKey == "" && layer.ApiKey != "" {
+			settings.ApiKey = layer.ApiKey
+			keyLayer = i
+		}
+		settings.DefaultFrom = firstNonEmpty(settings.DefaultFrom, layer.DefaultFrom)
+		settings.DefaultContactBookId = firstNonEmpty(settings.DefaultContactBookId, layer.DefaultContactBookId)
+		if settings.Timeout == 0 {
+			settings.Timeout = layer.Timeout
+		}
+	}
+
+	for _, layer := range layers[:keyLayer+1] {
+		settings.BaseUrl = firstNonEmpty(settings.BaseUrl, layer.BaseUrl)
+	}
+	settings.BaseUrl = firstNonEmpty(settings.BaseUrl, defaults.BaseUrl)
+
+	return settings, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,317 @@
+package unsend_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+const testConfig = `
+# profiles for every instance we run
+default_profile = "cloud"
+
+[profiles.cloud]
+api_key = "cloud-key"
+
+[profiles."self-hosted"]
+api_key = 'self-hosted-key' # literal string
+base_url = "https://unsend.example.com/api"
+timeout = "10s"
+default_from = "hello@example.com"
+default_contact_book_id = "book123"
+
+[profiles.legacy]
+api_key = "legacy-key"
+timeout = 5
+`
+
+func TestParseConfig(t *testing.T) {
+	config, err := unsend.ParseConfig(strings.NewReader(testConfig))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := &unsend.Config{
+		DefaultProfile: "cloud",
+		Profiles: map[string]unsend.Profile{
+			"cloud": {ApiKey: "cloud-key"},
+			"self-hosted": {
+				ApiKey:               "self-hosted-key",
+				BaseUrl:              "https://unsend.example.com/api",
+				Timeout:              10 * time.Second,
+				DefaultFrom:          "hello@example.com",
+				DefaultContactBookId: "book123",
+			},
+			"legacy": {ApiKey: "legacy-key", Timeout: 5 * time.Second},
+		},
+	}
+
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("expected config to be %+v, got %+v", expected, config)
+	}
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		config         string
+		expectedErrMsg string
+	}{
+		{
+			name:           "Unknown key",
+			config:         "[profiles.cloud]\napi_secret = \"x\"",
+			expectedErrMsg: "line 2: unknown key 'api_secret'",
+		},
+		{
+			name:           "Unknown section",
+			config:         "[servers.cloud]",
+			expectedErrMsg: "line 1: unknown section [servers.cloud]; expected [profiles.<name>]",
+		},
+		{
+			name:           "Missing default profile",
+			config:         "default_profile = \"cloud\"",
+			expectedErrMsg: "default_profile 'cloud' is not defined",
+		},
+		{
+			name:           "Invalid timeout",
+			config:         "[profiles.cloud]\ntimeout = \"soon\"",
+			expectedErrMsg: "line 2: timeout: time: invalid duration \"soon\"",
+		},
+		{
+			name:           "Unterminated string",
+			config:         "[profiles.cloud]\napi_key = \"abc",
+			expectedErrMsg: "line 2: invalid string \"abc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := unsend.ParseConfig(strings.NewReader(tt.config))
+			if err == nil || err.Error() != tt.expectedErrMsg {
+				t.Errorf("expected error %v, got %v", tt.expectedErrMsg, err)
+			}
+		})
+	}
+}
+
+func TestNewClientProfilePrecedence(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(configPath, []byte(testConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	selfHostedDefault := filepath.Join(t.TempDir(), "self-hosted.toml")
+	config := strings.Replace(testConfig, `default_profile = "cloud"`, `default_profile = "self-hosted"`, 1)
+	if err := os.WriteFile(selfHostedDefault, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		env             map[string]string
+		options         []unsend.ClientOption
+		expectedApiKey  string
+		expectedBaseUrl string
+		expectedTimeout time.Duration
+		expectedFrom    string
+	}{
+		{
+			name:            "Default profile",
+			expectedApiKey:  "cloud-key",
+			expectedBaseUrl: unsend.DEFAULT_BASE_URL,
+			expectedTimeout: unsend.DEFAULT_TIMEOUT,
+		},
+		{
+			name:            "Environment beats default profile",
+			env:             map[string]string{unsend.ENV_KEY_API_KEY: "env-key", unsend.ENV_KEY_BASE_URL: "https://env.example.com/api"},
+			expectedApiKey:  "env-key",
+			expectedBaseUrl: "https://env.example.com/api",
+			expectedTimeout: unsend.DEFAULT_TIMEOUT,
+		},
+		{
+			name:            "Named profile beats environment",
+			env:             map[string]string{unsend.ENV_KEY_API_KEY: "env-key", unsend.ENV_KEY_PROFILE: "self-hosted"},
+			expectedApiKey:  "self-hosted-key",
+			expectedBaseUrl: "https://unsend.example.com/api",
+			expectedTimeout: 10 * time.Second,
+			expectedFrom:    "hello@example.com",
+		},
+		{
+			name:            "Named profile key keeps its own base URL",
+			env:             map[string]string{unsend.ENV_KEY_BASE_URL: "https://env.example.com/api"},
+			options:         []unsend.ClientOption{unsend.WithProfile("legacy")},
+			expectedApiKey:  "legacy-key",
+			expectedBaseUrl: unsend.DEFAULT_BASE_URL,
+			expectedTimeout: 5 * time.Second,
+		},
+		{
+			name:            "WithProfile beats UNSEND_PROFILE",
+			env:             map[string]string{unsend.ENV_KEY_PROFILE: "legacy"},
+			options:         []unsend.ClientOption{unsend.WithProfile("self-hosted")},
+			expectedApiKey:  "self-hosted-key",
+			expectedBaseUrl: "https://unsend.example.com/api",
+			expectedTimeout: 10 * time.Second,
+			expectedFrom:    "hello@example.com",
+		},
+		{
+			name:            "Environment key isn't paired with the default profile's base URL",
+			env:             map[string]string{unsend.ENV_KEY_API_KEY: "env-key", unsend.ENV_KEY_CONFIG_FILE: selfHostedDefault},
+			expectedApiKey:  "env-key",
+			expectedBaseUrl: unsend.DEFAULT_BASE_URL,
+			expectedTimeout: 10 * time.Second,
+			expectedFrom:    "hello@example.com",
+		},
+		{
+			name:            "Option base URL applies to any key",
+			env:             map[string]string{unsend.ENV_KEY_API_KEY: "env-key"},
+			options:         []unsend.ClientOption{unsend.WithBaseUrl("https://option.example.com/api")},
+			expectedApiKey:  "env-key",
+			expectedBaseUrl: "https://option.example.com/api",
+			expectedTimeout: unsend.DEFAULT_TIMEOUT,
+		},
+		{
+			name: "Options beat everything",
+			env:  map[string]string{unsend.ENV_KEY_API_KEY: "env-key"},
+			options: []unsend.ClientOption{
+				unsend.WithProfile("self-hosted"),
+				unsend.WithApiKey("option-key"),
+				unsend.WithBaseUrl("https://option.example.com/api"),
+				unsend.WithTimeout(time.Second),
+			},
+			expectedApiKey:  "option-key",
+			expectedBaseUrl: "https://option.example.com/api",
+			expectedTimeout: time.Second,
+			expectedFrom:    "hello@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if path, ok := tt.env[unsend.ENV_KEY_CONFIG_FILE]; ok {
+				t.Setenv(unsend.ENV_KEY_CONFIG_FILE, path)
+			} else {
+				t.Setenv(unsend.ENV_KEY_CONFIG_FILE, configPath)
+			}
+			for _, key := range []string{unsend.ENV_KEY_API_KEY, unsend.ENV_KEY_BASE_URL, unsend.ENV_KEY_PROFILE} {
+				t.Setenv(key, tt.env[key])
+				if _, ok := tt.env[key]; !ok {
+					os.Unsetenv(key)
+				}
+			}
+
+			client, err := unsend.NewClient(tt.options...)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if client.ApiKey != tt.expectedApiKey {
+				t.Errorf("expected apiKey to be %s, got %s", tt.expectedApiKey, client.ApiKey)
+			}
+			if client.BaseUrl.String() != tt.expectedBaseUrl {
+				t.Errorf("expected baseUrl to be %s, got %s", tt.expectedBaseUrl, client.BaseUrl.String())
+			}
+			if client.Client.Timeout != tt.expectedTimeout {
+				t.Errorf("expected timeout to be %s, got %s", tt.expectedTimeout, client.Client.Timeout)
+			}
+			if client.DefaultFrom != tt.expectedFrom {
+				t.Errorf("expected default from to be %s, got %s", tt.expectedFrom, client.DefaultFrom)
+			}
+		})
+	}
+}
+
+func TestNewClientUnknownProfile(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(configPath, []byte(testConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := unsend.NewClient(unsend.WithConfigFile(configPath), unsend.WithProfile("staging"))
+	expectedErrMsg := "profile 'staging' not found in " + configPath
+	if err == nil || err.Error() != expectedErrMsg {
+		t.Errorf("expected error %v, got %v", expectedErrMsg, err)
+	}
+
+	_, err = unsend.NewClient(unsend.WithConfigFile(filepath.Join(t.TempDir(), "missing.toml")), unsend.WithProfile("cloud"))
+	if err == nil {
+		t.Errorf("expected error for a named profile without a config file")
+	}
+}
+
+func TestNewClientBrokenDefaultConfig(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "unsend", "config.toml")
+	os.MkdirAll(filepath.Dir(configPath), 0o700)
+	if err := os.WriteFile(configPath, []byte("[profiles.cloud\napi_key = \"cloud-key\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv(unsend.ENV_KEY_PROFILE, "")
+	t.Setenv(unsend.ENV_KEY_CONFIG_FILE, "")
+	os.Unsetenv(unsend.ENV_KEY_CONFIG_FILE)
+
+	if _, err := unsend.NewClient(unsend.WithApiKey("option-key")); err != nil {
+		t.Errorf("expected a broken default config file to be ignored, got %v", err)
+	}
+
+	if _, err := unsend.NewClient(unsend.WithApiKey("option-key"), unsend.WithProfile("cloud")); err == nil {
+		t.Errorf("expected error for a named profile in a broken config file")
+	}
+	if _, err := unsend.NewClient(unsend.WithApiKey("option-key"), unsend.WithConfigFile(configPath)); err == nil {
+		t.Errorf("expected error for a broken config file from WithConfigFile")
+	}
+
+	t.Setenv(unsend.ENV_KEY_CONFIG_FILE, configPath)
+	if _, err := unsend.NewClient(unsend.WithApiKey("option-key")); err == nil {
+		t.Errorf("expected error for a broken config file from %s", unsend.ENV_KEY_CONFIG_FILE)
+	}
+}
+
+func TestClientDefaultsFromProfile(t *testing.T) {
+	var paths []string
+	var froms []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if from, ok := body["from"].(string); ok {
+			froms = append(froms, from)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"emailId": "12345", "contactId": "12345"}`))
+	}))
+	defer server.Close()
+
+	client := &unsend.Client{
+		Client:               &http.Client{},
+		DefaultFrom:          "hello@example.com",
+		DefaultContactBookId: "book123",
+	}
+	client.BaseUrl, _ = url.Parse(server.URL)
+	client.Emails = &unsend.EmailsImpl{Client: client}
+	client.Contacts = &unsend.ContactsImpl{Client: client}
+
+	ctx := context.Background()
+	if _, err := client.Emails.SendEmail(ctx, unsend.SendEmailRequest{To: []string{"a@b.c"}}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := client.Contacts.CreateContact(ctx, unsend.CreateContactRequest{Email: "a@b.c"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(froms, []string{"hello@example.com"}) {
+		t.Errorf("expected default from to be sent, got %v", froms)
+	}
+	if len(paths) != 2 || paths[1] != "/api/v1/contactBooks/book123/contacts/" {
+		t.Errorf("expected default contact book to be used, got %v", paths)
+	}
+}
@@ -5,4 +5,19 @@ const VERSION = "0.1.0"
 const PACKAGE_NAME = "unsend-go"
 
 const ENV_KEY_API_KEY="UNSEND_API_KEY"
-const ENV_KEY_BASE_URL="UNSEND_BASE_URL"
\ No newline at end of file
+const ENV_KEY_BASE_URL="UNSEND_BASE_URL"
+const ENV_KEY_PROFILE = "UNSEND_PROFILE"
+const ENV_KEY_CONFIG_FILE = "UNSEND_CONFIG_FILE"
+const ENV_KEY_MODE = "UNSEND_MODE"
+const ENV_KEY_PREVIEW_ADDR = "UNSEND_PREVIEW_ADDR"
+const ENV_KEY_RECIPIENT_OVERRIDE = "UNSEND_RECIPIENT_OVERRIDE"
+
+const DOMAIN_STATUS_NOT_STARTED = "NOT_STARTED"
+const DOMAIN_STATUS_PENDING = "PENDING"
+const DOMAIN_STATUS_SUCCESS = "SUCCESS"
+const DOMAIN_STATUS_FAILED = "FAILED"
+const DOMAIN_STATUS_TEMPORARY_FAILURE = "TEMPORARY_FAILURE"
+
+const DNS_RECORD_TYPE_MX = "MX"
+const DNS_RECORD_TYPE_TXT = "TXT"
+const DEFAULT_DKIM_SELECTOR = "unsend"
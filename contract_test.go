@@ -0,0 +1,405 @@
+package unsend_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/QGeeDev/unsend-go"
+	"github.com/QGeeDev/unsend-go/internal/openapigen"
+	"github.com/QGeeDev/unsend-go/vcr"
+)
+
+// loadSpec reads the spec, the overlay and the local extensions named in
+// generate.go.
+func loadSpec(t *testing.T) ([]byte, []byte, [][]byte) {
+	t.Helper()
+	spec, err := os.ReadFile("openapi/unsend.json")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	overlay, err := os.ReadFile("openapi/overlay.json")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	campaigns, err := os.ReadFile("openapi/campaigns.local.json")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	return spec, overlay, [][]byte{campaigns}
+}
+
+func TestGeneratedCodeMatchesSpec(t *testing.T) {
+	spec, overlay, extensions := loadSpec(t)
+
+	files, err := openapigen.Generate(spec, overlay, extensions...)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for name, expected := range files {
+		actual, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !bytes.Equal(actual, expected) {
+			t.Errorf("%s is out of date with openapi/; run go generate", name)
+		}
+	}
+}
+
+func TestServicesMatchSpec(t *testing.T) {
+	spec, overlay, extensions := loadSpec(t)
+
+	api, err := openapigen.Load(spec, overlay, extensions...)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	services := map[string]reflect.Type{
+		"Emails":    reflect.TypeOf((*unsend.Emails)(nil)).Elem(),
+		"Contacts":  reflect.TypeOf((*unsend.Contacts)(nil)).Elem(),
+		"Domains":   reflect.TypeOf((*unsend.Domains)(nil)).Elem(),
+		"Campaigns": reflect.TypeOf((*unsend.Campaigns)(nil)).Elem(),
+	}
+
+	for _, operation := range api.Operations {
+		service, ok := services[operation.Service.Name]
+		if !ok {
+			t.Errorf("%s: no interface for service %s", operation.Mapping.OperationId, operation.Service.Name)
+			continue
+		}
+
+		method, ok := service.MethodByName(operation.Mapping.Method)
+		if !ok {
+			t.Errorf("%s: %s has no method %s", operation.Mapping.OperationId, operation.Service.Name, operation.Mapping.Method)
+			continue
+		}
+
+		response, err := api.GoType(operation.Operation.ResponseSchema())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if got := strings.ReplaceAll(method.Type.Out(0).Elem().String(), "unsend.", ""); got != response {
+			t.Errorf("%s.%s returns *%s, the spec describes %s", operation.Service.Name, operation.Mapping.Method, got, response)
+		}
+
+		request := ""
+		if method.Type.NumIn() > 1 {
+			request = method.Type.In(1).Name()
+		}
+		if request != operation.Mapping.Request {
+			t.Errorf("%s.%s takes %q, the spec describes %q", operation.Service.Name, operation.Mapping.Method, request, operation.Mapping.Request)
+		}
+	}
+}
+
+func TestCapabilitiesMatchSpec(t *testing.T) {
+	spec, _, extensions := loadSpec(t)
+
+	// A server that has the extensions' endpoints publishes them with the rest.
+	var document, extension map[string]interface{}
+	json.Unmarshal(spec, &document)
+	for _, data := range extensions {
+		json.Unmarshal(data, &extension)
+		for path, methods := range extension["paths"].(map[string]interface{}) {
+			document["paths"].(map[string]interface{})[path] = methods
+		}
+	}
+	spec, _ = json.Marshal(document)
+
+	client := newCapabilitiesClient(t, string(spec), map[string]*atomic.Int32{})
+	capabilities, err := client.Capabilities(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !capabilities.Detected || !capabilities.ScheduledEmails || !capabilities.DomainVerification || !capabilities.Campaigns {
+		t.Errorf("expected the features in the spec to be detected from it, got %+v", capabilities)
+	}
+	if !capabilities.Supports(http.MethodGet, "/v1/contactBooks/{id}/contacts/{id}") {
+		t.Errorf("expected the spec's operations to be supported")
+	}
+}
+
+func loadAPI(t *testing.T) *openapigen.API {
+	t.Helper()
+	spec, overlay, extensions := loadSpec(t)
+	api, err := openapigen.Load(spec, overlay, extensions...)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	return api
+}
+
+// newContractServer returns a client whose requests are checked against the
+// spec. The server answers each operation with its fixture from
+// testdata/contract and reports the last body sent to each operation.
+func newContractServer(t *testing.T, api *openapigen.API) (*unsend.Client, func() map[string][]byte) {
+	var mu sync.Mutex
+	bodies := map[string][]byte{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		operation := api.Match(r.Method, r.URL.Path)
+		if operation == nil {
+			t.Errorf("%s %s is not an operation of the spec", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies[operation.Mapping.OperationId] = body
+		mu.Unlock()
+
+		switch schema := operation.Operation.RequestSchema(); {
+		case schema != nil:
+			if err := api.Spec.ValidateStrict(schema, body); err != nil {
+				t.Errorf("%s request body %s doesn't match the spec:\n%v", operation.Mapping.Method, body, err)
+			}
+		case len(bytes.TrimSpace(body)) > 0:
+			t.Errorf("%s sent a body the spec doesn't describe: %s", operation.Mapping.Method, body)
+		}
+
+		fixture, err := os.ReadFile(filepath.Join("testdata", "contract", operation.Mapping.OperationId+".json"))
+		if err != nil {
+			t.Errorf("expected a fixture for %s, got %v", operation.Mapping.OperationId, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write(fixture)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := unsend.NewClient(
+		unsend.WithApiKey("test-api-key"),
+		unsend.WithBaseUrl(server.URL),
+		unsend.WithConfigFile(filepath.Join(t.TempDir(), "missing.toml")),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	return client, func() map[string][]byte {
+		mu.Lock()
+		defer mu.Unlock()
+		return bodies
+	}
+}
+
+func TestRequestsMatchSpec(t *testing.T) {
+	api := loadAPI(t)
+	client, bodies := newContractServer(t, api)
+
+	// sent lists fields the body must contain, for values the schema allows
+	// to be left out but that change what the API does when they are.
+	tests := []struct {
+		name        string
+		operationId string
+		sent        map[string]interface{}
+		call        func(ctx context.Context) error
+	}{
+		{"SendEmail", "sendEmail", nil, func(ctx context.Context) error {
+			_, err := client.Emails.SendEmail(ctx, unsend.SendEmailRequest{
+				To:          []string{"alice@example.com"},
+				From:        "Unsend <hello@unsend.dev>",
+				Subject:     "Welcome",
+				TemplateId:  "cm8bkab3p0001s3p3ecb5ta2b",
+				Variables:   map[string]interface{}{"name": "Alice"},
+				ReplyTo:     []string{"support@unsend.dev"},
+				Cc:          []string{"bob@example.com"},
+				Bcc:         []string{"audit@example.com"},
+				Text:        "Hello Alice",
+				Html:        "<p>Hello Alice</p>",
+				Attachments: []unsend.Attachments{{Filename: "terms.txt", Content: "VGVybXM="}},
+				ScheduledAt: "2024-06-02T09:00:00Z",
+			})
+			return err
+		}},
+		{"SendEmail with the deprecated ScheduleAt", "sendEmail", map[string]interface{}{"scheduledAt": "2024-06-02T09:00:00Z"}, func(ctx context.Context) error {
+			_, err := client.Emails.SendEmail(ctx, unsend.SendEmailRequest{
+				To: []string{"alice@example.com"}, From: "hello@unsend.dev", Text: "Hi", ScheduleAt: "2024-06-02T09:00:00Z",
+			})
+			return err
+		}},
+		{"GetEmail", "getEmail", nil, func(ctx context.Context) error {
+			_, err := client.Emails.GetEmail(ctx, unsend.GetEmailRequest{EmailId: "cm8bk9ymd0000s3p3hh4wqp3b"})
+			return err
+		}},
+		{"UpdateSchedule", "updateSchedule", nil, func(ctx context.Context) error {
+			_, err := client.Emails.UpdateSchedule(ctx, unsend.UpdateScheduleRequest{EmailId: "cm8bk9ymd0000s3p3hh4wqp3b", ScheduledAt: "2024-06-03T09:00:00Z"})
+			return err
+		}},
+		{"CancelSchedule", "cancelSchedule", nil, func(ctx context.Context) error {
+			_, err := client.Emails.CancelSchedule(ctx, unsend.CancelScheduleRequest{EmailId: "cm8bk9ymd0000s3p3hh4wqp3b"})
+			return err
+		}},
+		{"CreateContact", "createContact", nil, func(ctx context.Context) error {
+			_, err := client.Contacts.CreateContact(ctx, unsend.CreateContactRequest{
+				ContactBookId: "cm8ath8d20001s3p3if0mhoq7", Email: "alice@example.com", FirstName: "Alice", LastName: "Smith",
+				Properties: map[string]interface{}{"plan": "pro"}, Subscribed: true,
+			})
+			return err
+		}},
+		{"GetContact", "getContact", nil, func(ctx context.Context) error {
+			_, err := client.Contacts.GetContact(ctx, unsend.GetContactRequest{ContactBookId: "cm8ath8d20001s3p3if0mhoq7", ContactId: "cm8bkd1k60003s3p3ke6ofm8y"})
+			return err
+		}},
+		{"UpsertContact", "upsertContact", nil, func(ctx context.Context) error {
+			_, err := client.Contacts.UpsertContact(ctx, unsend.UpsertContactRequest{
+				ContactBookId: "cm8ath8d20001s3p3if0mhoq7", ContactId: "cm8bkd1k60003s3p3ke6ofm8y", Email: "alice@example.com",
+			})
+			return err
+		}},
+		{"UpdateContact unsubscribing", "updateContact", map[string]interface{}{"subscribed": false}, func(ctx context.Context) error {
+			_, err := client.Contacts.UpdateContact(ctx, unsend.UpdateContactRequest{
+				ContactBookId: "cm8ath8d20001s3p3if0mhoq7", ContactId: "cm8bkd1k60003s3p3ke6ofm8y", Subscribed: false,
+			})
+			return err
+		}},
+		{"DeleteContact", "deleteContact", nil, func(ctx context.Context) error {
+			_, err := client.Contacts.DeleteContact(ctx, unsend.DeleteContactRequest{ContactBookId: "cm8ath8d20001s3p3if0mhoq7", ContactId: "cm8bkd1k60003s3p3ke6ofm8y"})
+			return err
+		}},
+		{"GetDomains", "getDomains", nil, func(ctx context.Context) error {
+			_, err := client.Domains.GetDomains(ctx)
+			return err
+		}},
+		{"GetDomain", "getDomain", nil, func(ctx context.Context) error {
+			_, err := client.Domains.GetDomain(ctx, unsend.GetDomainRequest{DomainId: 3})
+			return err
+		}},
+		{"CreateDomain", "createDomain", nil, func(ctx context.Context) error {
+			_, err := client.Domains.CreateDomain(ctx, unsend.CreateDomainRequest{Name: "unsend.dev", Region: "us-east-1"})
+			return err
+		}},
+		{"VerifyDomain", "verifyDomain", nil, func(ctx context.Context) error {
+			_, err := client.Domains.VerifyDomain(ctx, unsend.VerifyDomainRequest{DomainId: 3})
+			return err
+		}},
+		{"DeleteDomain", "deleteDomain", nil, func(ctx context.Context) error {
+			_, err := client.Domains.DeleteDomain(ctx, unsend.DeleteDomainRequest{DomainId: 3})
+			return err
+		}},
+		{"CreateCampaign", "createCampaign", map[string]interface{}{"scheduledAt": "2024-06-03T09:00:00Z"}, func(ctx context.Context) error {
+			_, err := client.Campaigns.CreateCampaign(ctx, unsend.CreateCampaignRequest{
+				Name:          "June newsletter",
+				From:          "Unsend <news@unsend.dev>",
+				Subject:       "What's new in June",
+				ContactBookId: "cm8ath8d20001s3p3if0mhoq7",
+				PreviewText:   "Campaigns, webhooks and more",
+				Html:          "<p>Hello {{firstName}}</p>",
+				ReplyTo:       []string{"support@unsend.dev"},
+				ScheduledAt:   "2024-06-03T09:00:00Z",
+				BatchSize:     500,
+			})
+			return err
+		}},
+		{"GetCampaign", "getCampaign", nil, func(ctx context.Context) error {
+			_, err := client.Campaigns.GetCampaign(ctx, unsend.GetCampaignRequest{CampaignId: "cm9c2fzxk0001s3p3v1n2o5qa"})
+			return err
+		}},
+		{"ListCampaigns", "listCampaigns", nil, func(ctx context.Context) error {
+			_, err := client.Campaigns.ListCampaigns(ctx, unsend.ListCampaignsRequest{Page: 2, Status: unsend.CAMPAIGN_STATUS_SCHEDULED})
+			return err
+		}},
+		{"UpdateCampaign", "updateCampaign", nil, func(ctx context.Context) error {
+			_, err := client.Campaigns.UpdateCampaign(ctx, unsend.UpdateCampaignRequest{CampaignId: "cm9c2fzxk0001s3p3v1n2o5qa", Subject: "What's new this June"})
+			return err
+		}},
+		{"ScheduleCampaign", "scheduleCampaign", nil, func(ctx context.Context) error {
+			_, err := client.Campaigns.ScheduleCampaign(ctx, unsend.ScheduleCampaignRequest{CampaignId: "cm9c2fzxk0001s3p3v1n2o5qa", ScheduledAt: "2024-06-03T09:00:00Z", BatchSize: 500})
+			return err
+		}},
+		{"SendCampaign", "sendCampaign", nil, func(ctx context.Context) error {
+			_, err := client.Campaigns.SendCampaign(ctx, unsend.SendCampaignRequest{CampaignId: "cm9c2fzxk0001s3p3v1n2o5qa"})
+			return err
+		}},
+		{"PauseCampaign", "pauseCampaign", nil, func(ctx context.Context) error {
+			_, err := client.Campaigns.PauseCampaign(ctx, unsend.PauseCampaignRequest{CampaignId: "cm9c2fzxk0001s3p3v1n2o5qa"})
+			return err
+		}},
+		{"ResumeCampaign", "resumeCampaign", nil, func(ctx context.Context) error {
+			_, err := client.Campaigns.ResumeCampaign(ctx, unsend.ResumeCampaignRequest{CampaignId: "cm9c2fzxk0001s3p3v1n2o5qa"})
+			return err
+		}},
+		{"DeleteCampaign", "deleteCampaign", nil, func(ctx context.Context) error {
+			_, err := client.Campaigns.DeleteCampaign(ctx, unsend.DeleteCampaignRequest{CampaignId: "cm9c2fzxk0001s3p3v1n2o5qa"})
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(context.Background()); err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+
+			var body map[string]interface{}
+			json.Unmarshal(bodies()[tt.operationId], &body)
+			for field, value := range tt.sent {
+				if !reflect.DeepEqual(body[field], value) {
+					t.Errorf("expected %s to be sent as %v, got %s", field, value, bodies()[tt.operationId])
+				}
+			}
+		})
+	}
+
+	for _, operation := range api.Operations {
+		if _, ok := bodies()[operation.Mapping.OperationId]; !ok {
+			t.Errorf("expected a contract test for %s", operation.Mapping.OperationId)
+		}
+	}
+}
+
+func TestFixturesMatchSpec(t *testing.T) {
+	api := loadAPI(t)
+
+	for _, operation := range api.Operations {
+		fixture, err := os.ReadFile(filepath.Join("testdata", "contract", operation.Mapping.OperationId+".json"))
+		if err != nil {
+			t.Errorf("expected a fixture for %s, got %v", operation.Mapping.OperationId, err)
+			continue
+		}
+		if err := api.Spec.Validate(operation.Operation.ResponseSchema(), fixture); err != nil {
+			t.Errorf("fixture for %s doesn't match the spec:\n%v", operation.Mapping.OperationId, err)
+		}
+	}
+
+	cassettes, _ := filepath.Glob(filepath.Join("testdata", "cassettes", "*.json"))
+	for _, path := range cassettes {
+		cassette, err := vcr.Load(path)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		for i, interaction := range cassette.Interactions {
+			request := interaction.Request
+			operation := api.Match(request.Method, strings.SplitN(request.URL, "?", 2)[0])
+			if operation == nil {
+				t.Errorf("%s #%d: %s %s is not an operation of the spec", path, i, request.Method, request.URL)
+				continue
+			}
+			if schema := operation.Operation.RequestSchema(); schema != nil {
+				if err := api.Spec.ValidateStrict(schema, request.Body); err != nil {
+					t.Errorf("%s #%d: request body doesn't match the spec:\n%v", path, i, err)
+				}
+			}
+			if interaction.Response.StatusCode == http.StatusOK {
+				if err := api.Spec.Validate(operation.Operation.ResponseSchema(), interaction.Response.Body); err != nil {
+					t.Errorf("%s #%d: response body doesn't match the spec:\n%v", path, i, err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package unsend
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrNoCredentials = errors.New("[ERROR]: No API key available from credentials provider")
+
+// CredentialsProvider supplies the API key. UnsendTransport asks for it on
+// every request, so providers can rotate keys without a new Client.
+type CredentialsProvider interface {
+	ApiKey(ctx context.Context) (string, error)
+}
+
+type StaticCredentials struct {
+	Key string
+}
+
+func (c StaticCredentials) ApiKey(ctx context.Context) (string, error) {
+	if c.Key == "" {
+		return "", ErrNoCredentials
+	}
+	return c.Key, nil
+}
+
+// EnvCredentials reads the environment variable on each call.
+type EnvCredentials struct {
+	Variable string
+}
+
+func (c EnvCredentials) ApiKey(ctx context.Context) (string, error) {
+	key := strings.TrimSpace(os.Getenv(c.Variable))
+	if key == "" {
+		return "", fmt.Errorf("%w; %s is not set", ErrNoCredentials, c.Variable)
+	}
+	return key, nil
+}
+
+// FileCredentials reads the key from a file, such as a mounted secret, and
+// reloads it whenever the file's modification time or size changes.
+type FileCredentials struct {
+	Path string
+
+	mu      sync.Mutex
+	key     string
+	modTime time.Time
+	size    int64
+}
+
+func NewFileCredentials(path string) *FileCredentials {
+	return &FileCredentials{Path: path}
+}
+
+func (c *FileCredentials) ApiKey(ctx context.Context) (string, error) {
+	info, err := os.Stat(c.Path)
+	if err != nil {
+		return "", fmt.Errorf("[ERROR]: Failed to read API key file; %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.key != "" && info.ModTime().Equal(c.modTime) && info.Size() == c.size {
+		return c.key, nil
+	}
+
+	content, err := os.ReadFile(c.Path)
+	if err != nil {
+		return "", fmt.Errorf("[ERROR]: Failed to read API key file; %w", err)
+	}
+
+	key := strings.TrimSpace(string(content))
+	if key == "" {
+		return "", fmt.Errorf("%w; %s is empty", ErrNoCredentials, c.Path)
+	}
+
+	c.key = key
+	c.modTime = info.ModTime()
+	c.size = info.Size()
+	return c.key, nil
+}
+
+// ExecCredentials runs a command and uses its trimmed stdout as the key,
+// caching it for TTL. A zero TTL runs the command on every request.
+type ExecCredentials struct {
+	Command string
+	Args    []string
+	TTL     time.Duration
+
+	mu        sync.Mutex
+	key       string
+	fetchedAt time.Time
+}
+
+func NewExecCredentials(ttl time.Duration, command string, args ...string) *ExecCredentials {
+	return &ExecCredentials{
+		Command: command,
+		Args:    args,
+		TTL:     ttl,
+	}
+}
+
+func (c *ExecCredentials) ApiKey(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.key != "" && time.Since(c.fetchedAt) < c.TTL {
+		return c.key, nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, c.Command, c.Args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("[ERROR]: API key command failed; %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	key := strings.TrimSpace(stdout.String())
+	if key == "" {
+		return "", fmt.Errorf("%w; %s printed nothing", ErrNoCredentials, c.Command)
+	}
+
+	c.key = key
+	c.fetchedAt = time.Now()
+	return c.key, nil
+}
+
+// ChainCredentials returns the key from the first provider that has one.
+type ChainCredentials []CredentialsProvider
+
+func (c ChainCredentials) ApiKey(ctx context.Context) (string, error) {
+	var errs []error
+	for _, provider := range c {
+		key, err := provider.ApiKey(ctx)
+		if err == nil && key != "" {
+			return key, nil
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) == 0 {
+		return "", ErrNoCredentials
+	}
+	return "", errors.Join(errs...)
+}
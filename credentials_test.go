@@ -0,0 +1,172 @@
+package unsend_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+func TestCredentialsProviders(t *testing.T) {
+	t.Setenv("TEST_UNSEND_KEY", "env-key")
+
+	keyFile := filepath.Join(t.TempDir(), "api-key")
+	if err := os.WriteFile(keyFile, []byte("file-key\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		provider       unsend.CredentialsProvider
+		expectedKey    string
+		expectedNoCred bool
+	}{
+		{
+			name:        "Static",
+			provider:    unsend.StaticCredentials{Key: "static-key"},
+			expectedKey: "static-key",
+		},
+		{
+			name:        "Env",
+			provider:    unsend.EnvCredentials{Variable: "TEST_UNSEND_KEY"},
+			expectedKey: "env-key",
+		},
+		{
+			name:           "Env not set",
+			provider:       unsend.EnvCredentials{Variable: "TEST_UNSEND_MISSING"},
+			expectedNoCred: true,
+		},
+		{
+			name:        "File",
+			provider:    unsend.NewFileCredentials(keyFile),
+			expectedKey: "file-key",
+		},
+		{
+			name: "Chain skips empty providers",
+			provider: unsend.ChainCredentials{
+				unsend.EnvCredentials{Variable: "TEST_UNSEND_MISSING"},
+				unsend.StaticCredentials{},
+				unsend.NewFileCredentials(keyFile),
+			},
+			expectedKey: "file-key",
+		},
+		{
+			name: "Chain with no keys",
+			provider: unsend.ChainCredentials{
+				unsend.EnvCredentials{Variable: "TEST_UNSEND_MISSING"},
+				unsend.StaticCredentials{},
+			},
+			expectedNoCred: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := tt.provider.ApiKey(context.Background())
+			if tt.expectedNoCred {
+				if !errors.Is(err, unsend.ErrNoCredentials) {
+					t.Fatalf("expected ErrNoCredentials, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if key != tt.expectedKey {
+				t.Errorf("expected key to be %s, got %s", tt.expectedKey, key)
+			}
+		})
+	}
+}
+
+func TestFileCredentialsReloadsOnChange(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "api-key")
+	if err := os.WriteFile(keyFile, []byte("first-key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	provider := unsend.NewFileCredentials(keyFile)
+	if key, _ := provider.ApiKey(context.Background()); key != "first-key" {
+		t.Fatalf("expected first-key, got %s", key)
+	}
+
+	if err := os.WriteFile(keyFile, []byte("rotated-key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(keyFile, later, later)
+
+	if key, _ := provider.ApiKey(context.Background()); key != "rotated-key" {
+		t.Errorf("expected rotated-key after the file changed, got %s", key)
+	}
+}
+
+func TestExecCredentials(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	counter := filepath.Join(t.TempDir(), "runs")
+	provider := unsend.NewExecCredentials(time.Minute, "sh", "-c", "echo run >> "+counter+"; echo exec-key")
+
+	for i := 0; i < 2; i++ {
+		key, err := provider.ApiKey(context.Background())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if key != "exec-key" {
+			t.Errorf("expected exec-key, got %s", key)
+		}
+	}
+
+	runs, _ := os.ReadFile(counter)
+	if string(runs) != "run\n" {
+		t.Errorf("expected the command to run once within the TTL, got %q", runs)
+	}
+
+	failing := unsend.NewExecCredentials(0, "sh", "-c", "echo denied >&2; exit 1")
+	if _, err := failing.ApiKey(context.Background()); err == nil {
+		t.Errorf("expected error from failing command")
+	}
+}
+
+func TestTransportUsesCredentialsPerRequest(t *testing.T) {
+	var seen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	t.Setenv("TEST_UNSEND_KEY", "first-key")
+	t.Setenv(unsend.ENV_KEY_API_KEY, "")
+
+	client, err := unsend.NewClient(
+		unsend.WithBaseUrl(server.URL),
+		unsend.WithConfigFile(filepath.Join(t.TempDir(), "missing.toml")),
+		unsend.WithCredentials(unsend.EnvCredentials{Variable: "TEST_UNSEND_KEY"}),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := client.Domains.GetDomains(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	os.Setenv("TEST_UNSEND_KEY", "rotated-key")
+	if _, err := client.Domains.GetDomains(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []string{"Bearer first-key", "Bearer rotated-key"}
+	if len(seen) != 2 || seen[0] != expected[0] || seen[1] != expected[1] {
+		t.Errorf("expected Authorization headers %v, got %v", expected, seen)
+	}
+}
@@ -0,0 +1,266 @@
+package unsend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+const DNS_CHECK_OK = "OK"
+const DNS_CHECK_MISSING = "MISSING"
+const DNS_CHECK_MISMATCH = "MISMATCH"
+const DNS_CHECK_ERROR = "ERROR"
+
+// DnsResolver is the subset of *net.Resolver used by DnsChecker.
+type DnsResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+}
+
+type DnsRecordDiagnosis struct {
+	Record  DnsRecord
+	Fqdn    string
+	Status  string
+	Found   []string
+	Message string
+}
+
+type DnsCheckResult struct {
+	Domain  string
+	Records []DnsRecordDiagnosis
+}
+
+func (r *DnsCheckResult) Ok() bool {
+	for _, record := range r.Records {
+		if record.Status != DNS_CHECK_OK {
+			return false
+		}
+	}
+	return true
+}
+
+type DnsChecker struct {
+	Resolver DnsResolver
+}
+
+func NewDnsChecker(resolver DnsResolver) *DnsChecker {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	return &DnsChecker{Resolver: resolver}
+}
+
+func (c *DnsChecker) Check(ctx context.Context, domain GetDomainsResponse) (*DnsCheckResult, error) {
+	result := &DnsCheckResult{Domain: domain.Name}
+	zone := dnsZone(domain)
+
+	for _, record := range domain.RequiredDnsRecords() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		diagnosis := DnsRecordDiagnosis{
+			Record: record,
+			Fqdn:   dnsFqdn(record.Name, zone),
+		}
+
+		switch record.Type {
+		case DNS_RECORD_TYPE_MX:
+			c.checkMX(ctx, &diagnosis)
+		case DNS_RECORD_TYPE_TXT:
+			c.checkTXT(ctx, &diagnosis)
+		default:
+			diagnosis.Status = DNS_CHECK_ERROR
+			diagnosis.Message = fmt.Sprintf("unsupported record type %s", record.Type)
+		}
+
+		result.Records = append(result.Records, diagnosis)
+	}
+
+	return result, nil
+}
+
+func (c *DnsChecker) checkMX(ctx context.Context, diagnosis *DnsRecordDiagnosis) {
+	records, err := c.Resolver.LookupMX(ctx, diagnosis.Fqdn)
+	if err != nil {
+		setLookupError(diagnosis, err)
+		return
+	}
+
+	expected := normalizeHost(diagnosis.Record.Value)
+	for _, record := range records {
+		host := normalizeHost(record.Host)
+		diagnosis.Found = append(diagnosis.Found, fmt.Sprintf("%d %s", record.Pref, host))
+		if host == expected {
+			diagnosis.Status = DNS_CHECK_OK
+		}
+	}
+
+	switch {
+	case diagnosis.Status == DNS_CHECK_OK:
+	case len(records) == 0:
+		diagnosis.Status = DNS_CHECK_MISSING
+		diagnosis.Message = "no MX record found"
+	default:
+		diagnosis.Status = DNS_CHECK_MISMATCH
+		diagnosis.Message = fmt.Sprintf("expected MX host %s", expected)
+	}
+}
+
+func (c *DnsChecker) checkTXT(ctx context.Context, diagnosis *DnsRecordDiagnosis) {
+	records, err := c.Resolver.LookupTXT(ctx, diagnosis.Fqdn)
+	if err != nil {
+		setLookupError(diagnosis, err)
+		return
+	}
+
+	for _, record := range records {
+		diagnosis.Found = append(diagnosis.Found, normalizeTXT(record))
+	}
+
+	expected := normalizeTXT(diagnosis.Record.Value)
+	switch {
+	case strings.HasPrefix(expected, "v=spf1"):
+		checkSPF(diagnosis, expected)
+	case strings.HasPrefix(expected, "v=DMARC1"):
+		checkPrefixed(diagnosis, "v=DMARC1", "DMARC")
+	case strings.HasPrefix(expected, "p="):
+		checkDKIM(diagnosis, expected)
+	default:
+		checkExact(diagnosis, expected)
+	}
+}
+
+func checkSPF(diagnosis *DnsRecordDiagnosis, expected string) {
+	var spf []string
+	for _, record := range diagnosis.Found {
+		if strings.HasPrefix(record, "v=spf1") {
+			spf = append(spf, record)
+		}
+	}
+
+	if len(spf) == 0 {
+		diagnosis.Status = DNS_CHECK_MISSING
+		diagnosis.Message = "no SPF record found"
+		return
+	}
+	if len(spf) > 1 {
+		diagnosis.Status = DNS_CHECK_MISMATCH
+		diagnosis.Message = "multiple SPF records found; only one is allowed"
+		return
+	}
+
+	present := strings.Fields(spf[0])
+	for _, mechanism := range strings.Fields(expected) {
+		if !strings.HasPrefix(mechanism, "include:") {
+			continue
+		}
+		if !containsFold(present, mechanism) {
+			diagnosis.Status = DNS_CHECK_MISMATCH
+			diagnosis.Message = fmt.Sprintf("SPF record is missing %s", mechanism)
+			return
+		}
+	}
+
+	diagnosis.Status = DNS_CHECK_OK
+}
+
+func checkDKIM(diagnosis *DnsRecordDiagnosis, expected string) {
+	key := strings.ReplaceAll(expected, " ", "")
+	for _, record := range diagnosis.Found {
+		for _, tag := range strings.Split(strings.ReplaceAll(record, " ", ""), ";") {
+			if tag == key {
+				diagnosis.Status = DNS_CHECK_OK
+				return
+			}
+		}
+	}
+
+	if len(diagnosis.Found) == 0 {
+		diagnosis.Status = DNS_CHECK_MISSING
+		diagnosis.Message = "no DKIM record found"
+		return
+	}
+	diagnosis.Status = DNS_CHECK_MISMATCH
+	diagnosis.Message = "DKIM record does not contain the expected public key"
+}
+
+func checkPrefixed(diagnosis *DnsRecordDiagnosis, prefix string, label string) {
+	for _, record := range diagnosis.Found {
+		if strings.HasPrefix(record, prefix) {
+			diagnosis.Status = DNS_CHECK_OK
+			return
+		}
+	}
+
+	diagnosis.Status = DNS_CHECK_MISSING
+	diagnosis.Message = fmt.Sprintf("no %s record found", label)
+}
+
+func checkExact(diagnosis *DnsRecordDiagnosis, expected string) {
+	for _, record := range diagnosis.Found {
+		if record == expected {
+			diagnosis.Status = DNS_CHECK_OK
+			return
+		}
+	}
+
+	if len(diagnosis.Found) == 0 {
+		diagnosis.Status = DNS_CHECK_MISSING
+		diagnosis.Message = "no TXT record found"
+		return
+	}
+	diagnosis.Status = DNS_CHECK_MISMATCH
+	diagnosis.Message = fmt.Sprintf("expected TXT value %s", expected)
+}
+
+func setLookupError(diagnosis *DnsRecordDiagnosis, err error) {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		diagnosis.Status = DNS_CHECK_MISSING
+		diagnosis.Message = fmt.Sprintf("%s does not exist", diagnosis.Fqdn)
+		return
+	}
+
+	diagnosis.Status = DNS_CHECK_ERROR
+	diagnosis.Message = err.Error()
+}
+
+func dnsZone(domain GetDomainsResponse) string {
+	if domain.Subdomain != "" {
+		if zone, ok := strings.CutPrefix(domain.Name, domain.Subdomain+"."); ok {
+			return zone
+		}
+	}
+	return domain.Name
+}
+
+func dnsFqdn(name string, zone string) string {
+	name = strings.TrimSuffix(name, ".")
+	if name == "" || name == "@" {
+		return zone
+	}
+	if strings.EqualFold(name, zone) || strings.HasSuffix(strings.ToLower(name), "."+strings.ToLower(zone)) {
+		return name
+	}
+	return name + "." + zone
+}
+
+func normalizeHost(host string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(host), "."))
+}
+
+func normalizeTXT(value string) string {
+	return strings.Join(strings.Fields(strings.Trim(strings.TrimSpace(value), `"`)), " ")
+}
+
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,278 @@
+package unsend_test
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+type stubResolver struct {
+	txt map[string][]string
+	mx  map[string][]*net.MX
+}
+
+func (r *stubResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	records, ok := r.txt[name]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return records, nil
+}
+
+func (r *stubResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	records, ok := r.mx[name]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return records, nil
+}
+
+func TestDnsCheckerCheck(t *testing.T) {
+	domain := unsend.GetDomainsResponse{
+		Name:      "updates.unsend.dev",
+		Subdomain: "updates",
+		Region:    "us-east-1",
+		PublicKey: "key123",
+	}
+
+	tests := []struct {
+		name             string
+		resolver         *stubResolver
+		expectedStatuses []string
+		expectedOk       bool
+	}{
+		{
+			name: "All records published",
+			resolver: &stubResolver{
+				txt: map[string][]string{
+					"unsend._domainkey.updates.unsend.dev": {"v=DKIM1; k=rsa; p=key123"},
+					"mail.updates.unsend.dev":              {"v=spf1 include:amazonses.com ~all"},
+					"_dmarc.updates.unsend.dev":            {"v=DMARC1; p=quarantine;"},
+				},
+				mx: map[string][]*net.MX{
+					"mail.updates.unsend.dev": {{Host: "feedback-smtp.us-east-1.amazonses.com.", Pref: 10}},
+				},
+			},
+			expectedStatuses: []string{"OK", "OK", "OK", "OK"},
+			expectedOk:       true,
+		},
+		{
+			name:             "Nothing published",
+			resolver:         &stubResolver{},
+			expectedStatuses: []string{"MISSING", "MISSING", "MISSING", "MISSING"},
+			expectedOk:       false,
+		},
+		{
+			name: "Wrong values published",
+			resolver: &stubResolver{
+				txt: map[string][]string{
+					"unsend._domainkey.updates.unsend.dev": {"v=DKIM1; k=rsa; p=otherkey"},
+					"mail.updates.unsend.dev":              {"v=spf1 include:_spf.google.com ~all"},
+					"_dmarc.updates.unsend.dev":            {"v=DMARC1; p=none;"},
+				},
+				mx: map[string][]*net.MX{
+					"mail.updates.unsend.dev": {{Host: "mx.example.com.", Pref: 10}},
+				},
+			},
+			expectedStatuses: []string{"MISMATCH", "MISMATCH", "MISMATCH", "OK"},
+			expectedOk:       false,
+		},
+		{
+			name: "Duplicate SPF records",
+			resolver: &stubResolver{
+				txt: map[string][]string{
+					"unsend._domainkey.updates.unsend.dev": {"p=key123"},
+					"mail.updates.unsend.dev":              {"v=spf1 include:amazonses.com ~all", "v=spf1 -all"},
+					"_dmarc.updates.unsend.dev":            {"v=DMARC1; p=none;"},
+				},
+				mx: map[string][]*net.MX{
+					"mail.updates.unsend.dev": {{Host: "feedback-smtp.us-east-1.amazonses.com", Pref: 10}},
+				},
+			},
+			expectedStatuses: []string{"OK", "OK", "MISMATCH", "OK"},
+			expectedOk:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := unsend.NewDnsChecker(tt.resolver)
+			result, err := checker.Check(context.Background(), domain)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if len(result.Records) != len(tt.expectedStatuses) {
+				t.Fatalf("expected %d diagnoses, got %d", len(tt.expectedStatuses), len(result.Records))
+			}
+			for i, diagnosis := range result.Records {
+				if diagnosis.Status != tt.expectedStatuses[i] {
+					t.Errorf("expected %s %s to be %s, got %s (%s)", diagnosis.Record.Type, diagnosis.Fqdn, tt.expectedStatuses[i], diagnosis.Status, diagnosis.Message)
+				}
+			}
+			if result.Ok() != tt.expectedOk {
+				t.Errorf("expected Ok() to be %v, got %v", tt.expectedOk, result.Ok())
+			}
+		})
+	}
+}
+
+// dnsServer is a local stand-in for a DNS server, answering TXT and MX
+// queries over UDP from fixed records and NXDOMAIN for any other name.
+type dnsServer struct {
+	conn net.PacketConn
+	txt  map[string][][]string
+	mx   map[string][]*net.MX
+}
+
+func startDnsServer(t *testing.T, txt map[string][][]string, mx map[string][]*net.MX) *dnsServer {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	server := &dnsServer{conn: conn, txt: txt, mx: mx}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if response := server.answer(buf[:n]); response != nil {
+				conn.WriteTo(response, addr)
+			}
+		}
+	}()
+	return server
+}
+
+// Resolver returns a pure Go resolver that sends every query to the server.
+func (s *dnsServer) Resolver() *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "udp", s.conn.LocalAddr().String())
+		},
+	}
+}
+
+func (s *dnsServer) answer(query []byte) []byte {
+	if len(query) < 12 {
+		return nil
+	}
+
+	// Read the question's name and type; the answers point back at it.
+	var labels []string
+	offset := 12
+	for offset < len(query) && query[offset] != 0 {
+		length := int(query[offset])
+		if offset+1+length > len(query) {
+			return nil
+		}
+		labels = append(labels, string(query[offset+1:offset+1+length]))
+		offset += 1 + length
+	}
+	offset++
+	if offset+4 > len(query) {
+		return nil
+	}
+	name := strings.ToLower(strings.Join(labels, "."))
+	qtype := binary.BigEndian.Uint16(query[offset:])
+	question := query[12 : offset+4]
+
+	var answers [][]byte
+	_, hasTXT := s.txt[name]
+	_, hasMX := s.mx[name]
+	switch qtype {
+	case 16:
+		for _, record := range s.txt[name] {
+			var data []byte
+			for _, part := range record {
+				data = append(data, byte(len(part)))
+				data = append(data, part...)
+			}
+			answers = append(answers, dnsResource(16, data))
+		}
+	case 15:
+		for _, record := range s.mx[name] {
+			data := binary.BigEndian.AppendUint16(nil, record.Pref)
+			for _, label := range strings.Split(strings.TrimSuffix(record.Host, "."), ".") {
+				data = append(data, byte(len(label)))
+				data = append(data, label...)
+			}
+			answers = append(answers, dnsResource(15, append(data, 0)))
+		}
+	}
+
+	flags := uint16(0x8180)
+	if !hasTXT && !hasMX {
+		flags |= 3
+	}
+	response := append([]byte{}, query[:2]...)
+	response = binary.BigEndian.AppendUint16(response, flags)
+	response = binary.BigEndian.AppendUint16(response, 1)
+	response = binary.BigEndian.AppendUint16(response, uint16(len(answers)))
+	response = append(response, 0, 0, 0, 0)
+	response = append(response, question...)
+	for _, answer := range answers {
+		response = append(response, answer...)
+	}
+	return response
+}
+
+func dnsResource(rtype uint16, data []byte) []byte {
+	resource := []byte{0xc0, 12}
+	resource = binary.BigEndian.AppendUint16(resource, rtype)
+	resource = binary.BigEndian.AppendUint16(resource, 1)
+	resource = binary.BigEndian.AppendUint32(resource, 300)
+	resource = binary.BigEndian.AppendUint16(resource, uint16(len(data)))
+	return append(resource, data...)
+}
+
+func TestDnsCheckerWithDnsServer(t *testing.T) {
+	domain := unsend.GetDomainsResponse{
+		Name:      "updates.unsend.dev",
+		Subdomain: "updates",
+		Region:    "us-east-1",
+		PublicKey: "key123",
+	}
+
+	server := startDnsServer(t,
+		map[string][][]string{
+			// Long keys are published as several strings of one record.
+			"unsend._domainkey.updates.unsend.dev": {{"v=DKIM1; k=rsa; p=key", "123"}},
+			"mail.updates.unsend.dev":              {{"v=spf1 include:amazonses.com ~all"}, {"google-site-verification=abc"}},
+		},
+		map[string][]*net.MX{
+			"mail.updates.unsend.dev": {{Host: "Feedback-SMTP.us-east-1.amazonses.com", Pref: 10}},
+		},
+	)
+
+	result, err := unsend.NewDnsChecker(server.Resolver()).Check(context.Background(), domain)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := map[string]string{
+		"TXT unsend._domainkey.updates.unsend.dev": "OK",
+		"TXT mail.updates.unsend.dev":              "OK",
+		"MX mail.updates.unsend.dev":               "OK",
+		"TXT _dmarc.updates.unsend.dev":            "MISSING",
+	}
+	if len(result.Records) != len(expected) {
+		t.Fatalf("expected %d diagnoses, got %d", len(expected), len(result.Records))
+	}
+	for _, diagnosis := range result.Records {
+		key := string(diagnosis.Record.Type) + " " + diagnosis.Fqdn
+		if diagnosis.Status != expected[key] {
+			t.Errorf("expected %s to be %s, got %s (%s, found %q)", key, expected[key], diagnosis.Status, diagnosis.Message, diagnosis.Found)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package unsend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const DEFAULT_DOMAIN_POLL_INITIAL_INTERVAL = 10 * time.Second
+const DEFAULT_DOMAIN_POLL_MAX_INTERVAL = 5 * time.Minute
+const DEFAULT_DOMAIN_WAIT_TIMEOUT = time.Hour
+
+const DOMAIN_RECORD_STATUS = "STATUS"
+const DOMAIN_RECORD_DKIM = "DKIM"
+const DOMAIN_RECORD_SPF = "SPF"
+
+type WaitForDomainVerifiedRequest struct {
+	DomainId        int
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	// Timeout ends the wait when ctx has no deadline of its own, so a domain
+	// whose verification never started isn't polled forever. Defaults to 1h.
+	Timeout time.Duration
+	// OnStatusChange is called whenever the domain, DKIM or SPF status
+	// differs from the previous poll, including the first observation.
+	OnStatusChange func(change DomainStatusChange)
+}
+
+type DomainStatusChange struct {
+	Record   string
+	Previous string
+	Current  string
+	Domain   *GetDomainsResponse
+}
+
+type DomainVerificationError struct {
+	Domain     string
+	Status     string
+	DkimStatus string
+	SpfDetails string
+}
+
+func (e *DomainVerificationError) Error() string {
+	return fmt.Sprintf("[ERROR]: Domain '%s' failed verification; status: %s, dkim: %s, spf: %s", e.Domain, e.Status, e.DkimStatus, e.SpfDetails)
+}
+
+// WaitForDomainVerified polls the domain with exponential backoff until its
+// status is SUCCESS or FAILED, or ctx is done. Without a deadline on ctx it
+// gives up after request.Timeout. Temporary errors from
+// individual polls are retried, and the last one is returned alongside the
+// context error. Other errors, such as an unknown domain, end the wait.
+func (d *DomainsImpl) WaitForDomainVerified(ctx context.Context, request WaitForDomainVerifiedRequest) (*GetDomainsResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("[ERROR]: WaitForDomainVerifiedRequest not valid; %w", err)
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		timeout := request.Timeout
+		if timeout <= 0 {
+			timeout = DEFAULT_DOMAIN_WAIT_TIMEOUT
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	initial := request.InitialInterval
+	if initial <= 0 {
+		initial = DEFAULT_DOMAIN_POLL_INITIAL_INTERVAL
+	}
+	maxInterval := request.MaxInterval
+	if maxInterval < initial {
+		maxInterval = max(DEFAULT_DOMAIN_POLL_MAX_INTERVAL, initial)
+	}
+
+	var last *GetDomainsResponse
+	var lastErr error
+	interval := initial
+
+	for {
+		domain, err := d.GetDomain(ctx, GetDomainRequest{DomainId: request.DomainId})
+		if err != nil {
+			if !IsTemporary(err) {
+				return last, err
+			}
+			lastErr = err
+		} else {
+			lastErr = nil
+			if notifyDomainStatusChanges(last, domain, request.OnStatusChange) {
+				interval = initial
+			}
+			last = domain
+
+			switch domain.Status {
+			case DOMAIN_STATUS_SUCCESS:
+				return domain, nil
+			case DOMAIN_STATUS_FAILED:
+				return domain, &DomainVerificationError{
+					Domain:     domain.Name,
+					Status:     domain.Status,
+					DkimStatus: domain.DkimStatus,
+					SpfDetails: domain.SpfDetails,
+				}
+			}
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return last, errors.Join(ctx.Err(), lastErr)
+		case <-timer.C:
+		}
+
+		interval = min(interval*2, maxInterval)
+	}
+}
+
+func notifyDomainStatusChanges(previous *GetDomainsResponse, current *GetDomainsResponse, callback func(DomainStatusChange)) bool {
+	if previous == nil {
+		previous = &GetDomainsResponse{}
+	}
+
+	changes := []DomainStatusChange{
+		{Record: DOMAIN_RECORD_STATUS, Previous: previous.Status, Current: current.Status},
+		{Record: DOMAIN_RECORD_DKIM, Previous: previous.DkimStatus, Current: current.DkimStatus},
+		{Record: DOMAIN_RECORD_SPF, Previous: previous.SpfDetails, Current: current.SpfDetails},
+	}
+
+	changed := false
+	for _, change := range changes {
+		if change.Previous == change.Current {
+			continue
+		}
+		changed = true
+		if callback != nil {
+			change.Domain = current
+			callback(change)
+		}
+	}
+
+	return changed
+}
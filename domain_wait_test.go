@@ -0,0 +1,223 @@
+package unsend_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+func newDomainPollingServer(t *testing.T, responses []string) *httptest.Server {
+	t.Helper()
+
+	var mu sync.Mutex
+	calls := 0
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/domains/1" && r.Method == http.MethodGet {
+			mu.Lock()
+			response := responses[min(calls, len(responses)-1)]
+			calls++
+			mu.Unlock()
+
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(response))
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "not found"}`))
+		}
+	}))
+}
+
+func TestWaitForDomainVerified(t *testing.T) {
+	tests := []struct {
+		name            string
+		responses       []string
+		expectedStatus  string
+		expectedErr     bool
+		expectedChanges []string
+	}{
+		{
+			name: "Domain becomes verified",
+			responses: []string{
+				`{"id": 1, "name": "unsend.dev", "status": "PENDING", "dkimStatus": "PENDING", "spfDetails": "PENDING"}`,
+				`{"id": 1, "name": "unsend.dev", "status": "PENDING", "dkimStatus": "SUCCESS", "spfDetails": "PENDING"}`,
+				`{"id": 1, "name": "unsend.dev", "status": "SUCCESS", "dkimStatus": "SUCCESS", "spfDetails": "SUCCESS"}`,
+			},
+			expectedStatus: "SUCCESS",
+			expectedErr:    false,
+			expectedChanges: []string{
+				"STATUS:->PENDING", "DKIM:->PENDING", "SPF:->PENDING",
+				"DKIM:PENDING->SUCCESS",
+				"STATUS:PENDING->SUCCESS", "SPF:PENDING->SUCCESS",
+			},
+		},
+		{
+			name: "Domain fails verification",
+			responses: []string{
+				`{"id": 1, "name": "unsend.dev", "status": "PENDING", "dkimStatus": "PENDING", "spfDetails": "PENDING"}`,
+				`{"id": 1, "name": "unsend.dev", "status": "FAILED", "dkimStatus": "FAILED", "spfDetails": "SUCCESS"}`,
+			},
+			expectedStatus: "FAILED",
+			expectedErr:    true,
+			expectedChanges: []string{
+				"STATUS:->PENDING", "DKIM:->PENDING", "SPF:->PENDING",
+				"STATUS:PENDING->FAILED", "DKIM:PENDING->FAILED", "SPF:PENDING->SUCCESS",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newDomainPollingServer(t, tt.responses)
+			defer server.Close()
+
+			client := &unsend.Client{
+				Client: &http.Client{},
+			}
+			client.BaseUrl, _ = url.Parse(server.URL)
+			client.Domains = &unsend.DomainsImpl{Client: client}
+
+			var changes []string
+			domain, err := client.Domains.WaitForDomainVerified(context.Background(), unsend.WaitForDomainVerifiedRequest{
+				DomainId:        1,
+				InitialInterval: time.Millisecond,
+				MaxInterval:     5 * time.Millisecond,
+				OnStatusChange: func(change unsend.DomainStatusChange) {
+					changes = append(changes, change.Record+":"+change.Previous+"->"+change.Current)
+				},
+			})
+
+			var verificationErr *unsend.DomainVerificationError
+			if tt.expectedErr && !errors.As(err, &verificationErr) {
+				t.Fatalf("expected DomainVerificationError, got %v", err)
+			}
+			if !tt.expectedErr && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if domain.Status != tt.expectedStatus {
+				t.Errorf("expected status to be %s, got %s", tt.expectedStatus, domain.Status)
+			}
+			if !reflect.DeepEqual(changes, tt.expectedChanges) {
+				t.Errorf("expected changes to be %v, got %v", tt.expectedChanges, changes)
+			}
+		})
+	}
+}
+
+func TestWaitForDomainVerifiedContextExpiry(t *testing.T) {
+	server := newDomainPollingServer(t, []string{
+		`{"id": 1, "name": "unsend.dev", "status": "PENDING", "dkimStatus": "PENDING", "spfDetails": "PENDING"}`,
+	})
+	defer server.Close()
+
+	client := &unsend.Client{
+		Client: &http.Client{},
+	}
+	client.BaseUrl, _ = url.Parse(server.URL)
+	client.Domains = &unsend.DomainsImpl{Client: client}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	domain, err := client.Domains.WaitForDomainVerified(ctx, unsend.WaitForDomainVerifiedRequest{
+		DomainId:        1,
+		InitialInterval: time.Millisecond,
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if domain == nil || domain.Status != "PENDING" {
+		t.Errorf("expected last seen domain to be PENDING, got %v", domain)
+	}
+}
+
+func TestWaitForDomainVerifiedTimeout(t *testing.T) {
+	server := newDomainPollingServer(t, []string{
+		`{"id": 1, "name": "unsend.dev", "status": "NOT_STARTED", "dkimStatus": "NOT_STARTED", "spfDetails": "NOT_STARTED"}`,
+	})
+	defer server.Close()
+
+	client := &unsend.Client{
+		Client: &http.Client{},
+	}
+	client.BaseUrl, _ = url.Parse(server.URL)
+	client.Domains = &unsend.DomainsImpl{Client: client}
+
+	domain, err := client.Domains.WaitForDomainVerified(context.Background(), unsend.WaitForDomainVerifiedRequest{
+		DomainId:        1,
+		InitialInterval: time.Millisecond,
+		Timeout:         20 * time.Millisecond,
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the wait to time out, got %v", err)
+	}
+	if domain == nil || domain.Status != unsend.DOMAIN_STATUS_NOT_STARTED {
+		t.Errorf("expected last seen domain to be NOT_STARTED, got %v", domain)
+	}
+}
+
+func TestWaitForDomainVerifiedStopsOnPermanentErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		statuses      []int
+		domainId      int
+		expectedCalls int
+		expectedErr   func(err error) bool
+	}{
+		{"Unknown domain", []int{http.StatusNotFound}, 1, 1, func(err error) bool {
+			var apiErr *unsend.APIError
+			return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+		}},
+		{"Forbidden", []int{http.StatusForbidden}, 1, 1, func(err error) bool {
+			var apiErr *unsend.APIError
+			return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden
+		}},
+		{"Invalid request", nil, 0, 0, func(err error) bool {
+			var validationErr *unsend.ValidationError
+			return errors.As(err, &validationErr)
+		}},
+		{"Temporary errors are retried", []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK}, 1, 3, func(err error) bool {
+			return err == nil
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tt.statuses[min(calls, len(tt.statuses)-1)]
+				calls++
+				w.WriteHeader(status)
+				if status == http.StatusOK {
+					w.Write([]byte(`{"id": 1, "name": "unsend.dev", "status": "SUCCESS", "dkimStatus": "SUCCESS", "spfDetails": "SUCCESS"}`))
+				} else {
+					w.Write([]byte(`{"error": "nope"}`))
+				}
+			}))
+			defer server.Close()
+
+			client := &unsend.Client{Client: &http.Client{}}
+			client.BaseUrl, _ = url.Parse(server.URL)
+			client.Domains = &unsend.DomainsImpl{Client: client}
+
+			_, err := client.Domains.WaitForDomainVerified(context.Background(), unsend.WaitForDomainVerifiedRequest{
+				DomainId:        tt.domainId,
+				InitialInterval: time.Millisecond,
+			})
+			if !tt.expectedErr(err) {
+				t.Errorf("unexpected error %v", err)
+			}
+			if calls != tt.expectedCalls {
+				t.Errorf("expected %d calls, got %d", tt.expectedCalls, calls)
+			}
+		})
+	}
+}
@@ -2,45 +2,67 @@ package unsend
 
 import (
 	"context"
-	"net/http"
 )
 
 type Domains interface {
 	GetDomains(ctx context.Context) (*[]GetDomainsResponse, error)
-}
-
-type GetDomainsResponse struct {
-	Id            int    `json:"id"`
-	Name          string `json:"name"`
-	TeamId        int    `json:"teamId"`
-	Status        string `json:"status"`
-	PublicKey     string `json:"publicKey"`
-	CreatedAt     string `json:"createdAt"`
-	UpdatedAt     string `json:"updatedAt"`
-	Region        string `json:"region"`
-	ClickTracking bool   `json:"clickTracking"`
-	OpenTracking  bool   `json:"openTracking"`
-	DkimStatus    string `json:"dkimStatus,omitempty"`
-	SpfDetails    string `json:"spfDetails,omitempty"`
+	GetDomain(ctx context.Context, request GetDomainRequest) (*GetDomainsResponse, error)
+	CreateDomain(ctx context.Context, request CreateDomainRequest) (*GetDomainsResponse, error)
+	VerifyDomain(ctx context.Context, request VerifyDomainRequest) (*VerifyDomainResponse, error)
+	DeleteDomain(ctx context.Context, request DeleteDomainRequest) (*GetDomainsResponse, error)
+	WaitForDomainVerified(ctx context.Context, request WaitForDomainVerifiedRequest) (*GetDomainsResponse, error)
 }
 
 type DomainsImpl struct {
 	Client *Client
 }
 
-func (d *DomainsImpl) GetDomains(ctx context.Context) (*[]GetDomainsResponse, error) {
-	path := "api/v1/domains"
+// RequiredDnsRecords returns the records that must be published for the
+// domain. Servers that don't return dnsRecords get the records the Unsend
+// dashboard shows, derived from the region, public key and subdomain.
+func (d GetDomainsResponse) RequiredDnsRecords() []DnsRecord {
+	if len(d.DnsRecords) > 0 {
+		return d.DnsRecords
+	}
 
-	req, err := d.Client.NewRequest(http.MethodGet, path, nil)
-	if err != nil {
-		return nil, err
+	suffix := ""
+	if d.Subdomain != "" {
+		suffix = "." + d.Subdomain
 	}
 
-	response := new([]GetDomainsResponse)
-	err = d.Client.Execute(req, response)
-	if err != nil {
-		return nil, err
+	selector := d.DkimSelector
+	if selector == "" {
+		selector = DEFAULT_DKIM_SELECTOR
 	}
 
-	return response, nil
+	return []DnsRecord{
+		{
+			Type:     DNS_RECORD_TYPE_MX,
+			Name:     "mail" + suffix,
+			Value:    "feedback-smtp." + d.Region + ".amazonses.com",
+			Ttl:      "Auto",
+			Priority: "10",
+			Status:   d.SpfDetails,
+		},
+		{
+			Type:   DNS_RECORD_TYPE_TXT,
+			Name:   selector + "._domainkey" + suffix,
+			Value:  "p=" + d.PublicKey,
+			Ttl:    "Auto",
+			Status: d.DkimStatus,
+		},
+		{
+			Type:   DNS_RECORD_TYPE_TXT,
+			Name:   "mail" + suffix,
+			Value:  "v=spf1 include:amazonses.com ~all",
+			Ttl:    "Auto",
+			Status: d.SpfDetails,
+		},
+		{
+			Type:  DNS_RECORD_TYPE_TXT,
+			Name:  "_dmarc" + suffix,
+			Value: "v=DMARC1; p=none;",
+			Ttl:   "Auto",
+		},
+	}
 }
@@ -2,13 +2,16 @@ package unsend_test
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"path/filepath"
 	"reflect"
 	"testing"
 
 	"github.com/QGeeDev/unsend-go"
+	"github.com/QGeeDev/unsend-go/vcr"
 )
 
 func TestGetDomains(t *testing.T) {
@@ -92,3 +95,278 @@ func TestGetDomains(t *testing.T) {
 		})
 	}
 }
+
+func TestGetDomain(t *testing.T) {
+	client := &unsend.Client{
+		Client: &http.Client{},
+	}
+
+	client.Domains = &unsend.DomainsImpl{Client: client}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/domains/1" && r.Method == http.MethodGet {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{
+				"id": 1,
+				"name": "unsend.dev",
+				"teamId": 1,
+				"status": "PENDING",
+				"region": "us-east-1",
+				"publicKey": "key123",
+				"dkimStatus": "PENDING",
+				"spfDetails": "PENDING",
+				"dnsRecords": [{"type": "TXT", "name": "unsend._domainkey", "value": "p=key123", "ttl": "Auto", "status": "PENDING"}]
+			}`))
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client.BaseUrl, _ = url.Parse(server.URL)
+
+	tests := []struct {
+		name           string
+		request        unsend.GetDomainRequest
+		expectedDomain *unsend.GetDomainsResponse
+		expectedErrMsg string
+	}{
+		{
+			name:    "Valid request",
+			request: unsend.GetDomainRequest{DomainId: 1},
+			expectedDomain: &unsend.GetDomainsResponse{
+				Id:         1,
+				Name:       "unsend.dev",
+				TeamId:     1,
+				Status:     "PENDING",
+				Region:     "us-east-1",
+				PublicKey:  "key123",
+				DkimStatus: "PENDING",
+				SpfDetails: "PENDING",
+				DnsRecords: []unsend.DnsRecord{
+					{Type: "TXT", Name: "unsend._domainkey", Value: "p=key123", Ttl: "Auto", Status: "PENDING"},
+				},
+			},
+			expectedErrMsg: "",
+		},
+		{
+			name:           "Not found request",
+			request:        unsend.GetDomainRequest{DomainId: 2},
+			expectedDomain: nil,
+			expectedErrMsg: `received non-2xx response: 404 - {"error": "not found"}`,
+		},
+		{
+			name:           "Invalid request",
+			request:        unsend.GetDomainRequest{},
+			expectedDomain: nil,
+			expectedErrMsg: "[ERROR]: GetDomainRequest not valid; ['DomainId' is required]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			response, err := client.Domains.GetDomain(ctx, tt.request)
+			if err != nil && tt.expectedErrMsg == "" {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if err == nil && tt.expectedErrMsg != "" {
+				t.Fatalf("expected error %v, got no error", tt.expectedErrMsg)
+			}
+			if err != nil && tt.expectedErrMsg != "" && err.Error() != tt.expectedErrMsg {
+				t.Fatalf("expected error %v, got %v", tt.expectedErrMsg, err)
+			}
+			if !reflect.DeepEqual(response, tt.expectedDomain) {
+				t.Errorf("expected response to be %v, got %v", tt.expectedDomain, response)
+			}
+		})
+	}
+}
+
+func TestCreateDomain(t *testing.T) {
+	client := &unsend.Client{
+		Client: &http.Client{},
+	}
+
+	client.Domains = &unsend.DomainsImpl{Client: client}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/domains" && r.Method == http.MethodPost {
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": 3, "name": "` + body["name"] + `", "region": "` + body["region"] + `", "status": "NOT_STARTED", "publicKey": "key123"}`))
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client.BaseUrl, _ = url.Parse(server.URL)
+
+	tests := []struct {
+		name           string
+		request        unsend.CreateDomainRequest
+		expectedID     int
+		expectedErrMsg string
+	}{
+		{
+			name:           "Valid request",
+			request:        unsend.CreateDomainRequest{Name: "mail.unsend.dev", Region: "us-east-1"},
+			expectedID:     3,
+			expectedErrMsg: "",
+		},
+		{
+			name:           "Invalid request",
+			request:        unsend.CreateDomainRequest{Name: "mail.unsend.dev"},
+			expectedID:     0,
+			expectedErrMsg: "[ERROR]: CreateDomainRequest not valid; ['Region' is required]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			response, err := client.Domains.CreateDomain(ctx, tt.request)
+			if err != nil && tt.expectedErrMsg == "" {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if err == nil && tt.expectedErrMsg != "" {
+				t.Fatalf("expected error %v, got no error", tt.expectedErrMsg)
+			}
+			if err != nil && tt.expectedErrMsg != "" && err.Error() != tt.expectedErrMsg {
+				t.Fatalf("expected error %v, got %v", tt.expectedErrMsg, err)
+			}
+			if response != nil && (response.Id != tt.expectedID || response.Name != tt.request.Name) {
+				t.Errorf("expected domain %d named %s, got %d named %s", tt.expectedID, tt.request.Name, response.Id, response.Name)
+			}
+		})
+	}
+}
+
+func TestVerifyDomain(t *testing.T) {
+	client := &unsend.Client{
+		Client: &http.Client{},
+	}
+
+	client.Domains = &unsend.DomainsImpl{Client: client}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/domains/1/verify" && r.Method == http.MethodPut {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"message": "Domain verification started"}`))
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client.BaseUrl, _ = url.Parse(server.URL)
+
+	response, err := client.Domains.VerifyDomain(context.Background(), unsend.VerifyDomainRequest{DomainId: 1})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if response.Message != "Domain verification started" {
+		t.Errorf("expected message to be 'Domain verification started', got '%s'", response.Message)
+	}
+}
+
+func TestDeleteDomain(t *testing.T) {
+	client := &unsend.Client{
+		Client: &http.Client{},
+	}
+
+	client.Domains = &unsend.DomainsImpl{Client: client}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/domains/1" && r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": 1, "name": "unsend.dev"}`))
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client.BaseUrl, _ = url.Parse(server.URL)
+
+	response, err := client.Domains.DeleteDomain(context.Background(), unsend.DeleteDomainRequest{DomainId: 1})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if response.Id != 1 {
+		t.Errorf("expected deleted domain ID to be 1, got %d", response.Id)
+	}
+}
+
+func TestRequiredDnsRecords(t *testing.T) {
+	domain := unsend.GetDomainsResponse{
+		Name:       "mail.unsend.dev",
+		Subdomain:  "mail",
+		Region:     "eu-west-1",
+		PublicKey:  "key123",
+		DkimStatus: "SUCCESS",
+		SpfDetails: "PENDING",
+	}
+
+	expected := []unsend.DnsRecord{
+		{Type: "MX", Name: "mail.mail", Value: "feedback-smtp.eu-west-1.amazonses.com", Ttl: "Auto", Priority: "10", Status: "PENDING"},
+		{Type: "TXT", Name: "unsend._domainkey.mail", Value: "p=key123", Ttl: "Auto", Status: "SUCCESS"},
+		{Type: "TXT", Name: "mail.mail", Value: "v=spf1 include:amazonses.com ~all", Ttl: "Auto", Status: "PENDING"},
+		{Type: "TXT", Name: "_dmarc.mail", Value: "v=DMARC1; p=none;", Ttl: "Auto"},
+	}
+
+	if records := domain.RequiredDnsRecords(); !reflect.DeepEqual(records, expected) {
+		t.Errorf("expected records to be %v, got %v", expected, records)
+	}
+}
+
+func TestDomainsCassette(t *testing.T) {
+	recorder, err := vcr.New(filepath.Join("testdata", "cassettes", "domains.json"), vcr.Options{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	client, err := unsend.NewClient(
+		unsend.WithApiKey("test-api-key"),
+		unsend.WithBaseUrl("http://unsend.invalid"),
+		unsend.WithConfigFile(filepath.Join(t.TempDir(), "missing.toml")),
+		unsend.WithTransport(recorder),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	domains, err := client.Domains.GetDomains(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := []unsend.GetDomainsResponse{{
+		Id:           3,
+		Name:         "unsend.dev",
+		TeamId:       1,
+		Status:       unsend.DOMAIN_STATUS_SUCCESS,
+		PublicKey:    "MIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQC",
+		CreatedAt:    "2024-06-01T09:12:44.120Z",
+		UpdatedAt:    "2024-06-01T09:20:03.511Z",
+		Region:       "us-east-1",
+		OpenTracking: true,
+		DkimStatus:   unsend.DOMAIN_STATUS_SUCCESS,
+		SpfDetails:   unsend.DOMAIN_STATUS_SUCCESS,
+		Subdomain:    "mail",
+		DkimSelector: "unsend",
+	}}
+	if !reflect.DeepEqual(*domains, expected) {
+		t.Errorf("expected %+v, got %+v", expected, *domains)
+	}
+
+	verify, err := client.Domains.VerifyDomain(context.Background(), unsend.VerifyDomainRequest{DomainId: 3})
+	if err != nil || verify.Message != "Domain verification started" {
+		t.Errorf("expected recorded verify response, got %+v, %v", verify, err)
+	}
+}
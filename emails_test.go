@@ -2,6 +2,7 @@ package unsend_test
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -330,4 +331,51 @@ func TestCancelSchedule(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestSendEmailScheduledAt(t *testing.T) {
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		w.Write([]byte(`{"emailId": "12345"}`))
+	}))
+	defer server.Close()
+
+	client := &unsend.Client{Client: &http.Client{}}
+	client.Emails = &unsend.EmailsImpl{Client: client}
+	client.BaseUrl, _ = url.Parse(server.URL)
+
+	requests := []unsend.SendEmailRequest{
+		{To: []string{"a@b.c"}, From: "test@unsend.dev", ScheduledAt: "2021-01-01T00:00:00Z"},
+		{To: []string{"a@b.c"}, From: "test@unsend.dev", ScheduleAt: "2021-01-01T00:00:00Z"},
+	}
+	for _, request := range requests {
+		if _, err := client.Emails.SendEmail(context.Background(), request); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	for _, body := range bodies {
+		if body["scheduledAt"] != "2021-01-01T00:00:00Z" || body["scheduleAt"] != nil {
+			t.Errorf("expected the schedule to be sent as scheduledAt, got %v", body)
+		}
+	}
+}
+
+func TestSendEmailRequestKeepsScheduleAtInJSON(t *testing.T) {
+	saved := `{"to":["a@b.c"],"from":"test@unsend.dev","scheduleAt":"2021-01-01T00:00:00Z"}`
+
+	var request unsend.SendEmailRequest
+	if err := json.Unmarshal([]byte(saved), &request); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if request.ScheduleAt != "2021-01-01T00:00:00Z" || request.ScheduledAt != "" {
+		t.Errorf("expected the old key to be read into ScheduleAt, got %+v", request)
+	}
+
+	encoded, err := json.Marshal(request)
+	if err != nil || string(encoded) != saved {
+		t.Errorf("expected %s, got %s, %v", saved, encoded, err)
+	}
+}
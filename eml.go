@@ -0,0 +1,303 @@
+package unsend
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const HEADER_EMAIL_ID = "X-Unsend-Email-Id"
+
+// WriteMessage renders the request as an RFC 5322 message that mail clients
+// can open and ParseMessage can read back. Bcc recipients are kept so the
+// file can be re-sent as is.
+func (req SendEmailRequest) WriteMessage(w io.Writer) error {
+	req.moveScheduleAt()
+	date := time.Now()
+	if at, err := time.Parse(time.RFC3339, req.ScheduledAt); err == nil {
+		date = at
+	}
+
+	return writeMessage(w, message{
+		from:        req.From,
+		to:          req.To,
+		cc:          req.Cc,
+		bcc:         req.Bcc,
+		replyTo:     req.ReplyTo,
+		subject:     req.Subject,
+		date:        date,
+		text:        req.Text,
+		html:        req.Html,
+		attachments: req.Attachments,
+		extra:       [][2]string{{HEADER_TEMPLATE_ID, req.TemplateId}},
+	})
+}
+
+// WriteMessage renders a sent email as an RFC 5322 message for archiving or
+// opening in a mail client. The email id is kept in X-Unsend-Email-Id.
+func (resp GetEmailResponse) WriteMessage(w io.Writer) error {
+	date := time.Now()
+	if at, err := time.Parse(time.RFC3339, resp.CreatedAt); err == nil {
+		date = at
+	}
+
+	return writeMessage(w, message{
+		id:      resp.Id,
+		from:    resp.From,
+		to:      resp.To,
+		cc:      resp.Cc,
+		bcc:     resp.Bcc,
+		replyTo: resp.ReplyTo,
+		subject: resp.Subject,
+		date:    date,
+		text:    resp.Text,
+		html:    resp.Html,
+		extra:   [][2]string{{HEADER_EMAIL_ID, resp.Id}},
+	})
+}
+
+type message struct {
+	id          string
+	from        string
+	to          []string
+	cc          []string
+	bcc         []string
+	replyTo     []string
+	subject     string
+	date        time.Time
+	text        string
+	html        string
+	attachments []Attachments
+	extra       [][2]string
+}
+
+func writeMessage(w io.Writer, m message) error {
+	var buf bytes.Buffer
+
+	// Values are written as they are, so a line break in one would start a
+	// header of its own.
+	for _, field := range append([][2]string{{"Message-Id", m.id}}, m.extra...) {
+		if strings.ContainsAny(field[1], "\r\n") {
+			return fmt.Errorf("%s %q contains a line break", field[0], field[1])
+		}
+	}
+
+	header := [][2]string{
+		{"Date", m.date.Format(time.RFC1123Z)},
+		{"Message-Id", messageId(m.id, m.from)},
+	}
+	addresses := []struct {
+		name   string
+		values []string
+	}{
+		{"From", []string{m.from}},
+		{"To", m.to},
+		{"Cc", m.cc},
+		{"Bcc", m.bcc},
+		{"Reply-To", m.replyTo},
+	}
+	for _, field := range addresses {
+		formatted, err := formatAddresses(field.values)
+		if err != nil {
+			return fmt.Errorf("%s: %w", field.name, err)
+		}
+		header = append(header, [2]string{field.name, formatted})
+	}
+	header = append(header, [2]string{"Subject", mime.QEncoding.Encode("utf-8", m.subject)})
+	header = append(header, m.extra...)
+	header = append(header, [2]string{"MIME-Version", "1.0"})
+
+	for _, field := range header {
+		if field[1] != "" {
+			fmt.Fprintf(&buf, "%s: %s\r\n", field[0], field[1])
+		}
+	}
+
+	if len(m.attachments) == 0 {
+		if err := writeBody(&buf, nil, m.text, m.html); err != nil {
+			return err
+		}
+		_, err := w.Write(buf.Bytes())
+		return err
+	}
+
+	mixed := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: %s\r\n\r\n", mime.FormatMediaType("multipart/mixed", map[string]string{"boundary": mixed.Boundary()}))
+
+	if err := writeBody(&buf, mixed, m.text, m.html); err != nil {
+		return err
+	}
+	for _, attachment := range m.attachments {
+		if err := writeAttachment(mixed, attachment); err != nil {
+			return err
+		}
+	}
+	if err := mixed.Close(); err != nil {
+		return err
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeBody writes the text and HTML bodies as a single part, or as
+// multipart/alternative when both are set. With a nil parent the body is
+// written straight after the message header.
+func writeBody(buf *bytes.Buffer, parent *multipart.Writer, text, html string) error {
+	if text != "" && html != "" {
+		// The boundary is needed for the part header before the writer for
+		// the part's body exists.
+		boundary := multipart.NewWriter(nil).Boundary()
+		contentType := mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": boundary})
+
+		var body io.Writer = buf
+		if parent != nil {
+			part, err := parent.CreatePart(textproto.MIMEHeader{"Content-Type": {contentType}})
+			if err != nil {
+				return err
+			}
+			body = part
+		} else {
+			fmt.Fprintf(buf, "Content-Type: %s\r\n\r\n", contentType)
+		}
+
+		alternative := multipart.NewWriter(body)
+		if err := alternative.SetBoundary(boundary); err != nil {
+			return err
+		}
+		for _, part := range [][2]string{{"text/plain", text}, {"text/html", html}} {
+			writer, err := alternative.CreatePart(textPartHeader(part[0]))
+			if err != nil {
+				return err
+			}
+			if err := writeQuotedPrintable(writer, part[1]); err != nil {
+				return err
+			}
+		}
+		return alternative.Close()
+	}
+
+	mediaType, content := "text/plain", text
+	if html != "" {
+		mediaType, content = "text/html", html
+	}
+
+	partHeader := textPartHeader(mediaType)
+	if parent != nil {
+		part, err := parent.CreatePart(partHeader)
+		if err != nil {
+			return err
+		}
+		return writeQuotedPrintable(part, content)
+	}
+
+	for _, key := range []string{"Content-Type", "Content-Transfer-Encoding"} {
+		fmt.Fprintf(buf, "%s: %s\r\n", key, partHeader.Get(key))
+	}
+	buf.WriteString("\r\n")
+	return writeQuotedPrintable(buf, content)
+}
+
+func textPartHeader(mediaType string) textproto.MIMEHeader {
+	return textproto.MIMEHeader{
+		"Content-Type":              {mime.FormatMediaType(mediaType, map[string]string{"charset": "utf-8"})},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	}
+}
+
+func writeQuotedPrintable(w io.Writer, content string) error {
+	qp := quotedprintable.NewWriter(w)
+	// Keep line breaks as CRLF so the body survives transport unchanged.
+	content = strings.ReplaceAll(strings.ReplaceAll(content, "\r\n", "\n"), "\n", "\r\n")
+	if _, err := io.WriteString(qp, content); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+func writeAttachment(mixed *multipart.Writer, attachment Attachments) error {
+	content, err := base64.StdEncoding.DecodeString(attachment.Content)
+	if err != nil {
+		return fmt.Errorf("attachment '%s' is not valid base64: %w", attachment.Filename, err)
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(attachment.Filename))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	part, err := mixed.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename})},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return err
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(content)
+	for len(encoded) > 76 {
+		if _, err := io.WriteString(part, encoded[:76]+"\r\n"); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+	_, err = io.WriteString(part, encoded+"\r\n")
+	return err
+}
+
+// formatAddresses renders addresses for a header, encoding non-ASCII display
+// names. Values that don't parse are written unchanged, unless they contain a
+// line break.
+func formatAddresses(addresses []string) (string, error) {
+	formatted := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		if address == "" {
+			continue
+		}
+		parsed, err := mail.ParseAddress(address)
+		switch {
+		case err != nil && strings.ContainsAny(address, "\r\n"):
+			return "", fmt.Errorf("address %q contains a line break", address)
+		case err != nil:
+			formatted = append(formatted, address)
+		case parsed.Name == "":
+			formatted = append(formatted, parsed.Address)
+		default:
+			formatted = append(formatted, parsed.String())
+		}
+	}
+	// Fold long lists so header lines stay within the recommended length.
+	if len(formatted) > 1 && len(strings.Join(formatted, ", ")) > 72 {
+		return strings.Join(formatted, ",\r\n "), nil
+	}
+	return strings.Join(formatted, ", "), nil
+}
+
+// messageId builds a Message-Id from the email id, or a random one, at the
+// sender's domain.
+func messageId(id string, from string) string {
+	domain := "unsend.localhost"
+	if parsed, err := mail.ParseAddress(from); err == nil {
+		if _, host, ok := strings.Cut(parsed.Address, "@"); ok && host != "" {
+			domain = host
+		}
+	}
+
+	if id == "" {
+		var b [16]byte
+		rand.Read(b[:])
+		id = fmt.Sprintf("%x", b)
+	}
+	return "<" + id + "@" + domain + ">"
+}
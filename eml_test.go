@@ -0,0 +1,141 @@
+package unsend_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/mail"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+func TestSendEmailRequestWriteMessageRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		request unsend.SendEmailRequest
+	}{
+		{
+			name: "Text only",
+			request: unsend.SendEmailRequest{
+				From:    "hello@unsend.dev",
+				To:      []string{"a@b.c"},
+				Subject: "Plain",
+				Text:    "Hello\nWorld",
+			},
+		},
+		{
+			name: "Alternative bodies and attachments",
+			request: unsend.SendEmailRequest{
+				From:    `"Zoë" <hello@unsend.dev>`,
+				To:      []string{"a@b.c", "b@b.c", "c@b.c", "d@b.c", "e@b.c", "f@b.c", "g@b.c"},
+				Cc:      []string{"cc@b.c"},
+				Bcc:     []string{"bcc@b.c"},
+				ReplyTo: []string{"reply@unsend.dev"},
+				Subject: "Ünïcode subject that is long enough to need more than one encoded word",
+				Text:    "Hello = World",
+				Html:    "<p>" + strings.Repeat("long line ", 20) + "</p>",
+				Attachments: []unsend.Attachments{
+					{Filename: "report.csv", Content: base64.StdEncoding.EncodeToString(bytes.Repeat([]byte("a,b\n"), 40))},
+					{Filename: "naïve.bin", Content: base64.StdEncoding.EncodeToString([]byte{0, 1, 2})},
+				},
+			},
+		},
+		{
+			name: "Template",
+			request: unsend.SendEmailRequest{
+				From:       "hello@unsend.dev",
+				To:         []string{"a@b.c"},
+				TemplateId: "welcome",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.request.WriteMessage(&buf); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			for _, line := range strings.Split(buf.String(), "\r\n") {
+				if len(line) > 998 {
+					t.Errorf("expected lines within 998 characters, got %d", len(line))
+				}
+			}
+
+			parsed, err := unsend.ParseMessage(&buf)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(*parsed, tt.request) {
+				t.Errorf("expected %+v, got %+v", tt.request, *parsed)
+			}
+		})
+	}
+}
+
+func TestGetEmailResponseWriteMessage(t *testing.T) {
+	email := unsend.GetEmailResponse{
+		Id:        "email123",
+		From:      "hello@unsend.dev",
+		To:        []string{"a@b.c"},
+		Subject:   "Sent",
+		Html:      "<p>Hi</p>",
+		CreatedAt: "2024-05-01T10:00:00Z",
+	}
+
+	var buf bytes.Buffer
+	if err := email.WriteMessage(&buf); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	message, err := mail.ReadMessage(&buf)
+	if err != nil {
+		t.Fatalf("expected a valid message, got %v", err)
+	}
+
+	expected := map[string]string{
+		"X-Unsend-Email-Id": "email123",
+		"Message-Id":        "<email123@unsend.dev>",
+		"Date":              "Wed, 01 May 2024 10:00:00 +0000",
+		"Content-Type":      "text/html; charset=utf-8",
+	}
+	for key, value := range expected {
+		if got := message.Header.Get(key); got != value {
+			t.Errorf("expected %s to be %q, got %q", key, value, got)
+		}
+	}
+}
+
+func TestWriteMessageRejectsLineBreaksInHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		request unsend.SendEmailRequest
+	}{
+		{"From", unsend.SendEmailRequest{From: "hello@unsend.dev\r\nBcc: victim@b.c", To: []string{"a@b.c"}}},
+		{"To", unsend.SendEmailRequest{From: "hello@unsend.dev", To: []string{"a@b.c", "x\nBcc: victim@b.c"}}},
+		{"Reply-To", unsend.SendEmailRequest{From: "hello@unsend.dev", To: []string{"a@b.c"}, ReplyTo: []string{"x\rBcc: victim@b.c"}}},
+		{"Template", unsend.SendEmailRequest{From: "hello@unsend.dev", To: []string{"a@b.c"}, TemplateId: "welcome\r\nBcc: victim@b.c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.request.WriteMessage(&buf); err == nil || !strings.Contains(err.Error(), "line break") {
+				t.Errorf("expected an error for the line break, got %v", err)
+			}
+		})
+	}
+
+	// Addresses that don't parse but are a single line are still written.
+	var buf bytes.Buffer
+	request := unsend.SendEmailRequest{From: "hello@unsend.dev", To: []string{"undisclosed-recipients:;"}, Subject: "Hi\r\nBcc: victim@b.c"}
+	if err := request.WriteMessage(&buf); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if strings.Contains(buf.String(), "\r\nBcc:") || !strings.Contains(buf.String(), "To: undisclosed-recipients:;\r\n") {
+		t.Errorf("expected the subject to be encoded and the address kept, got\n%s", buf.String())
+	}
+}
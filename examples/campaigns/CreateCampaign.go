@@ -0,0 +1,30 @@
+package examples
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+func CreateCampaign() {
+	client, err := unsend.NewClient()
+
+	if err != nil {
+		fmt.Printf("[ERROR] - %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	request := &unsend.CreateCampaignRequest{
+		Name:          "June newsletter",
+		From:          "news@unsend.dev",
+		Subject:       "What's new in June",
+		ContactBookId: "book123",
+		Html:          "<p>Hello {{firstName}}</p>",
+	}
+
+	response, _ := client.Campaigns.CreateCampaign(context.Background(), *request)
+
+	fmt.Println(response)
+}
@@ -0,0 +1,28 @@
+package examples
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+func ScheduleCampaign() {
+	client, err := unsend.NewClient()
+
+	if err != nil {
+		fmt.Printf("[ERROR] - %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	request := &unsend.ScheduleCampaignRequest{
+		CampaignId:  "campaign123",
+		ScheduledAt: "2025-06-03T09:00:00Z",
+		BatchSize:   500,
+	}
+
+	response, _ := client.Campaigns.ScheduleCampaign(context.Background(), *request)
+
+	fmt.Println(response)
+}
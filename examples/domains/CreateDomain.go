@@ -0,0 +1,33 @@
+package examples
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+func CreateDomain() {
+	client, err := unsend.NewClient()
+
+	if err != nil {
+		fmt.Printf("[ERROR] - %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	request := &unsend.CreateDomainRequest{
+		Name:   "updates.qgtest.dev",
+		Region: "eu-west-2",
+	}
+
+	response, err := client.Domains.CreateDomain(context.Background(), *request)
+	if err != nil {
+		fmt.Printf("[ERROR] - %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	for _, record := range response.RequiredDnsRecords() {
+		fmt.Printf("%s\t%s\t%s\n", record.Type, record.Name, record.Value)
+	}
+}
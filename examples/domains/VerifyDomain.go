@@ -0,0 +1,26 @@
+package examples
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+func VerifyDomain() {
+	client, err := unsend.NewClient()
+
+	if err != nil {
+		fmt.Printf("[ERROR] - %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	request := &unsend.VerifyDomainRequest{
+		DomainId: 1,
+	}
+
+	response, _ := client.Domains.VerifyDomain(context.Background(), *request)
+
+	fmt.Println(response)
+}
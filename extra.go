@@ -0,0 +1,160 @@
+package unsend
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Response types keep the fields this version of the SDK doesn't know about
+// in Extra, so new server features can be read before the SDK is updated:
+//
+//	var region string
+//	json.Unmarshal(domain.Extra["newField"], &region)
+//
+// Extra is nil when the response has no unknown fields, and is written back
+// out by MarshalJSON. WithResponse gives the raw body of the whole response.
+// The generated response types call the helpers below from their
+// UnmarshalJSON and MarshalJSON methods.
+
+// unmarshalWithExtra decodes data into v and stores the keys v has no field
+// for in extra.
+func unmarshalWithExtra(data []byte, v interface{}, extra *map[string]json.RawMessage) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+
+	*extra = nil
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	known := knownFields(reflect.TypeOf(v).Elem())
+	for key, value := range fields {
+		if known[strings.ToLower(key)] {
+			continue
+		}
+		if *extra == nil {
+			*extra = map[string]json.RawMessage{}
+		}
+		(*extra)[key] = value
+	}
+	return nil
+}
+
+func marshalWithExtra(v interface{}, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+
+	known := knownFields(reflect.TypeOf(v))
+	keys := make([]string, 0, len(extra))
+	for key := range extra {
+		if !known[strings.ToLower(key)] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	// Append the extra fields after the known ones, keeping the field order.
+	buf := bytes.NewBuffer(data[:len(data)-1])
+	for _, key := range keys {
+		name, _ := json.Marshal(key)
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		if err := json.Compact(buf, extra[key]); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+var knownFieldsCache sync.Map
+
+// knownFields returns the lower-cased JSON keys decoded into t, matching the
+// case-insensitive lookup encoding/json uses.
+func knownFields(t reflect.Type) map[string]bool {
+	if cached, ok := knownFieldsCache.Load(t); ok {
+		return cached.(map[string]bool)
+	}
+
+	known := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		known[strings.ToLower(name)] = true
+	}
+
+	knownFieldsCache.Store(t, known)
+	return known
+}
+
+// logSchemaDrift logs, at debug, the fields in result the SDK doesn't know.
+func (c *Client) logSchemaDrift(req *http.Request, result interface{}) {
+	if c.Logger == nil || !c.Logger.Enabled(req.Context(), slog.LevelDebug) {
+		return
+	}
+
+	unknown := map[string]bool{}
+	collectExtra(reflect.ValueOf(result), unknown)
+	if len(unknown) == 0 {
+		return
+	}
+
+	fields := make([]string, 0, len(unknown))
+	for field := range unknown {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	c.Logger.DebugContext(req.Context(), "unsend response has fields unknown to the SDK",
+		"method", req.Method, "path", req.URL.Path, "fields", fields)
+}
+
+func collectExtra(v reflect.Value, unknown map[string]bool) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			collectExtra(v.Elem(), unknown)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			collectExtra(v.Index(i), unknown)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if field.Name == "Extra" && field.Type.Kind() == reflect.Map {
+				for _, key := range v.Field(i).MapKeys() {
+					unknown[v.Type().Name()+"."+key.String()] = true
+				}
+				continue
+			}
+			collectExtra(v.Field(i), unknown)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package unsend_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+func TestUnknownResponseFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"id": 1,
+			"name": "unsend.dev",
+			"status": "SUCCESS",
+			"sendingEnabled": true,
+			"limits": {"daily": 1000},
+			"dnsRecords": [{"type": "TXT", "name": "unsend._domainkey", "value": "p=key", "recommended": true}]
+		}`))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	client, err := unsend.NewClient(
+		unsend.WithApiKey("test-api-key"),
+		unsend.WithBaseUrl(server.URL),
+		unsend.WithConfigFile(filepath.Join(t.TempDir(), "missing.toml")),
+		unsend.WithLogger(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	domain, err := client.Domains.GetDomain(context.Background(), unsend.GetDomainRequest{DomainId: 1})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := map[string]json.RawMessage{
+		"sendingEnabled": json.RawMessage(`true`),
+		"limits":         json.RawMessage(`{"daily": 1000}`),
+	}
+	if !reflect.DeepEqual(domain.Extra, expected) {
+		t.Errorf("expected extra fields %s, got %s", expected, domain.Extra)
+	}
+	if string(domain.DnsRecords[0].Extra["recommended"]) != "true" {
+		t.Errorf("expected nested extra fields, got %v", domain.DnsRecords[0].Extra)
+	}
+
+	if !strings.Contains(logs.String(), "unsend response has fields unknown to the SDK") ||
+		!strings.Contains(logs.String(), "DnsRecord.recommended") ||
+		!strings.Contains(logs.String(), "GetDomainsResponse.sendingEnabled") {
+		t.Errorf("expected schema drift to be logged, got %s", logs.String())
+	}
+
+	encoded, err := json.Marshal(domain)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.HasPrefix(string(encoded), `{"id":1,"name":"unsend.dev"`) ||
+		!strings.Contains(string(encoded), `"limits":{"daily":1000},"sendingEnabled":true}`) ||
+		!strings.Contains(string(encoded), `"recommended":true`) {
+		t.Errorf("expected extra fields to be written after the known ones, got %s", encoded)
+	}
+}
+
+func TestKnownResponseFields(t *testing.T) {
+	var email unsend.GetEmailResponse
+	if err := json.Unmarshal([]byte(`{"id": "email123", "TO": ["a@b.c"], "emailEvents": [{"status": "SENT"}]}`), &email); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if email.Extra != nil || email.EmailEvents[0].Extra != nil || email.To[0] != "a@b.c" {
+		t.Errorf("expected no extra fields, got %+v", email)
+	}
+
+	encoded, _ := json.Marshal(unsend.EmailIdResponse{EmailId: "email123"})
+	if string(encoded) != `{"emailId":"email123"}` {
+		t.Errorf("expected plain encoding without extra fields, got %s", encoded)
+	}
+}
@@ -0,0 +1,9 @@
+package unsend
+
+// The request and response types, their validators and the service methods
+// are generated from the OpenAPI document in openapi/. openapi/unsend.json is
+// the upstream document, kept as published; endpoints it doesn't describe yet
+// are in the openapi/*.local.json extensions. Change those or
+// openapi/overlay.json and run go generate, rather than editing the *_gen.go
+// files.
+//go:generate go run ./internal/cmd/openapigen -spec openapi/unsend.json -extension openapi/campaigns.local.json -overlay openapi/overlay.json -out .
@@ -0,0 +1,185 @@
+package unsend
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const IDEMPOTENCY_KEY_HEADER = "Idempotency-Key"
+
+const DEFAULT_IDEMPOTENCY_WINDOW = 24 * time.Hour
+
+var ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different request")
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey returns a context that sends key as the Idempotency-Key
+// of the request it is passed to.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+func IdempotencyKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key, ok && key != ""
+}
+
+// NewIdempotencyKey returns a random UUID v4.
+func NewIdempotencyKey() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate idempotency key: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+type IdempotencyOptions struct {
+	// AutoGenerate adds a random key to SendEmail and contact writes that
+	// don't have one from WithIdempotencyKey. Generated keys let retries and
+	// the API deduplicate a request, but are not kept in the cache.
+	AutoGenerate bool
+	// Window is how long a successful response is replayed for a repeated
+	// key. Defaults to 24h.
+	Window time.Duration
+}
+
+// IdempotencyCache remembers successful responses by Idempotency-Key so a
+// repeated key within the window returns the first response without calling
+// the API again. Concurrent requests with the same key wait for the first.
+type IdempotencyCache struct {
+	Window time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*idempotencyEntry
+	now     func() time.Time
+}
+
+type idempotencyEntry struct {
+	fingerprint string
+	done        chan struct{}
+	body        []byte
+	ok          bool
+	expires     time.Time
+}
+
+func NewIdempotencyCache(window time.Duration) *IdempotencyCache {
+	if window <= 0 {
+		window = DEFAULT_IDEMPOTENCY_WINDOW
+	}
+
+	return &IdempotencyCache{
+		Window:  window,
+		entries: map[string]*idempotencyEntry{},
+		now:     time.Now,
+	}
+}
+
+// Len returns the number of keys the cache holds, including expired keys that
+// have not been evicted yet.
+func (c *IdempotencyCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// begin claims key for a request. It returns the cached body if the key
+// already succeeded, or a finish func the caller must call with the outcome.
+func (c *IdempotencyCache) begin(ctx context.Context, key, fingerprint string) ([]byte, func(body []byte, ok bool), error) {
+	for {
+		c.mu.Lock()
+		c.evict()
+		entry, found := c.entries[key]
+		if !found {
+			entry = &idempotencyEntry{fingerprint: fingerprint, done: make(chan struct{})}
+			c.entries[key] = entry
+			c.mu.Unlock()
+			return nil, func(body []byte, ok bool) { c.finish(key, entry, body, ok) }, nil
+		}
+		c.mu.Unlock()
+
+		if entry.fingerprint != fingerprint {
+			return nil, nil, ErrIdempotencyKeyReused
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-entry.done:
+		}
+
+		if entry.ok {
+			return entry.body, nil, nil
+		}
+		// The earlier request failed and released the key, so try again.
+	}
+}
+
+func (c *IdempotencyCache) finish(key string, entry *idempotencyEntry, body []byte, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ok {
+		entry.body = body
+		entry.ok = true
+		entry.expires = c.now().Add(c.Window)
+	} else if c.entries[key] == entry {
+		delete(c.entries, key)
+	}
+	close(entry.done)
+}
+
+// idempotencyFingerprint identifies what a key was used for, so reusing a key
+// for a different email is an error rather than a silent replay.
+func idempotencyFingerprint(req *http.Request) string {
+	sum := sha256.Sum256(requestBody(req))
+	return req.Method + " " + req.URL.Path + " " + hex.EncodeToString(sum[:])
+}
+
+func (c *IdempotencyCache) evict() {
+	now := c.now()
+	for key, entry := range c.entries {
+		if entry.ok && now.After(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// applyIdempotencyKey sets the Idempotency-Key header of writes from the
+// request context, or generates one for email and contact writes when
+// enabled. Reads made on the way, such as sender verification, never take the
+// caller's key. It reports whether the key was generated: a generated key is
+// never sent again, so its response is not worth caching.
+func (c *Client) applyIdempotencyKey(req *http.Request) (bool, error) {
+	if req.Header.Get(IDEMPOTENCY_KEY_HEADER) != "" || req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return false, nil
+	}
+
+	if key, ok := IdempotencyKeyFromContext(req.Context()); ok {
+		req.Header.Set(IDEMPOTENCY_KEY_HEADER, key)
+		return false, nil
+	}
+
+	if !c.AutoIdempotencyKeys {
+		return false, nil
+	}
+
+	switch endpointGroup(req.URL.Path) {
+	case ENDPOINT_GROUP_EMAILS, ENDPOINT_GROUP_CONTACTS, ENDPOINT_GROUP_CAMPAIGNS:
+		key, err := NewIdempotencyKey()
+		if err != nil {
+			return false, err
+		}
+		req.Header.Set(IDEMPOTENCY_KEY_HEADER, key)
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,241 @@
+package unsend_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+var testEmail = unsend.SendEmailRequest{
+	To:      []string{"a@b.c"},
+	From:    "test@unsend.dev",
+	Subject: "Test email",
+	Text:    "Hello, World!",
+}
+
+func newIdempotencyTestClient(serverUrl string, policy *unsend.RetryPolicy, autoGenerate bool) *unsend.Client {
+	client := &unsend.Client{
+		Client:              &http.Client{},
+		RetryPolicy:         policy,
+		AutoIdempotencyKeys: autoGenerate,
+		IdempotencyCache:    unsend.NewIdempotencyCache(time.Minute),
+	}
+	client.BaseUrl, _ = url.Parse(serverUrl)
+	client.Emails = &unsend.EmailsImpl{Client: client}
+	client.Contacts = &unsend.ContactsImpl{Client: client}
+	return client
+}
+
+func TestIdempotencyKeyHeader(t *testing.T) {
+	var mu sync.Mutex
+	keys := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys[r.Method+" "+r.URL.Path] = r.Header.Get(unsend.IDEMPOTENCY_KEY_HEADER)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"emailId": "12345", "contactId": "c1", "id": "12345"}`))
+	}))
+	defer server.Close()
+
+	client := newIdempotencyTestClient(server.URL, nil, true)
+	ctx := context.Background()
+
+	if _, err := client.Emails.SendEmail(unsend.WithIdempotencyKey(ctx, "caller-key"), testEmail); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if keys["POST /api/v1/emails"] != "caller-key" {
+		t.Errorf("expected caller supplied key, got %q", keys["POST /api/v1/emails"])
+	}
+
+	if _, err := client.Contacts.CreateContact(ctx, unsend.CreateContactRequest{ContactBookId: "book123", Email: "test@example.com"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(keys["POST /api/v1/contactBooks/book123/contacts/"]) != 36 {
+		t.Errorf("expected generated key for contact write, got %q", keys["POST /api/v1/contactBooks/book123/contacts/"])
+	}
+
+	if _, err := client.Emails.GetEmail(ctx, unsend.GetEmailRequest{EmailId: "12345"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if keys["GET /api/v1/emails/12345"] != "" {
+		t.Errorf("expected no key for reads, got %q", keys["GET /api/v1/emails/12345"])
+	}
+}
+
+func TestIdempotencyCacheReplaysResponse(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"emailId": "12345"}`))
+	}))
+	defer server.Close()
+
+	client := newIdempotencyTestClient(server.URL, nil, false)
+	ctx := unsend.WithIdempotencyKey(context.Background(), "send-once")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Emails.SendEmail(ctx, testEmail)
+			if err != nil || resp.EmailId != "12345" {
+				t.Errorf("expected replayed email 12345, got %v, %v", resp, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("expected 1 API call for a repeated key, got %d", calls)
+	}
+
+	_, err := client.Emails.CancelSchedule(ctx, unsend.CancelScheduleRequest{EmailId: "12345"})
+	if !errors.Is(err, unsend.ErrIdempotencyKeyReused) {
+		t.Errorf("expected ErrIdempotencyKeyReused, got %v", err)
+	}
+}
+
+func TestIdempotencyCacheSkipsGeneratedKeys(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"emailId": "12345"}`))
+	}))
+	defer server.Close()
+
+	client := newIdempotencyTestClient(server.URL, nil, true)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.Emails.SendEmail(ctx, testEmail); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	if client.IdempotencyCache.Len() != 0 {
+		t.Errorf("expected generated keys not to be cached, got %d keys", client.IdempotencyCache.Len())
+	}
+
+	if _, err := client.Emails.SendEmail(unsend.WithIdempotencyKey(ctx, "caller-key"), testEmail); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if client.IdempotencyCache.Len() != 1 {
+		t.Errorf("expected the caller's key to be cached, got %d keys", client.IdempotencyCache.Len())
+	}
+}
+
+func TestIdempotencyKeyWithDifferentBody(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"emailId": "12345"}`))
+	}))
+	defer server.Close()
+
+	client := newIdempotencyTestClient(server.URL, nil, false)
+	ctx := unsend.WithIdempotencyKey(context.Background(), "send-once")
+
+	if _, err := client.Emails.SendEmail(ctx, testEmail); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	other := testEmail
+	other.To = []string{"someone-else@b.c"}
+	if _, err := client.Emails.SendEmail(ctx, other); !errors.Is(err, unsend.ErrIdempotencyKeyReused) {
+		t.Errorf("expected ErrIdempotencyKeyReused for a different email, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 API call, got %d", calls)
+	}
+}
+
+func TestIdempotencyKeyNotUsedByReads(t *testing.T) {
+	var mu sync.Mutex
+	keys := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys[r.Method+" "+r.URL.Path] = r.Header.Get(unsend.IDEMPOTENCY_KEY_HEADER)
+		mu.Unlock()
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`[{"id": 1, "name": "unsend.dev", "status": "SUCCESS", "dkimStatus": "SUCCESS", "spfDetails": "SUCCESS"}]`))
+			return
+		}
+		w.Write([]byte(`{"emailId": "12345"}`))
+	}))
+	defer server.Close()
+
+	client := newIdempotencyTestClient(server.URL, nil, false)
+	client.Domains = &unsend.DomainsImpl{Client: client}
+	client.SenderVerifier = unsend.NewSenderVerifier(client.Domains, time.Minute)
+	ctx := unsend.WithIdempotencyKey(context.Background(), "send-once")
+
+	if _, err := client.Emails.SendEmail(ctx, testEmail); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if keys["GET /api/v1/domains"] != "" || keys["POST /api/v1/emails"] != "send-once" {
+		t.Errorf("expected the key on the send only, got %v", keys)
+	}
+}
+
+func TestRetryPolicyOnlyRetriesKeyedPosts(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls[r.Method]++
+		attempt := calls[r.Method]
+		mu.Unlock()
+		if attempt < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error": "unavailable"}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"emailId": "12345", "id": "12345"}`))
+	}))
+	defer server.Close()
+
+	policy := &unsend.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+	ctx := context.Background()
+
+	client := newIdempotencyTestClient(server.URL, policy, false)
+	_, err := client.Emails.SendEmail(ctx, testEmail)
+	var apiErr *unsend.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected APIError with 503, got %v", err)
+	}
+	if err.Error() != `received non-2xx response: 503 - {"error": "unavailable"}` {
+		t.Errorf("unexpected error message %q", err.Error())
+	}
+	if calls[http.MethodPost] != 1 {
+		t.Errorf("expected a POST without a key to be sent once, got %d", calls[http.MethodPost])
+	}
+
+	calls = map[string]int{}
+	client = newIdempotencyTestClient(server.URL, policy, true)
+	resp, err := client.Emails.SendEmail(ctx, testEmail)
+	if err != nil {
+		t.Fatalf("expected retries to succeed, got %v", err)
+	}
+	if resp.EmailId != "12345" || calls[http.MethodPost] != 3 {
+		t.Errorf("expected 3 attempts ending in 12345, got %d attempts and %q", calls[http.MethodPost], resp.EmailId)
+	}
+
+	if _, err := client.Emails.GetEmail(ctx, unsend.GetEmailRequest{EmailId: "12345"}); err != nil {
+		t.Fatalf("expected GET to be retried, got %v", err)
+	}
+	if calls[http.MethodGet] != 3 {
+		t.Errorf("expected 3 GET attempts, got %d", calls[http.MethodGet])
+	}
+}
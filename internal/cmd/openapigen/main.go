@@ -0,0 +1,44 @@
+// Command openapigen writes the generated SDK files. It is run by go generate
+// from the root of the module.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/QGeeDev/unsend-go/internal/openapigen"
+)
+
+func main() {
+	specPath := flag.String("spec", "openapi/unsend.json", "OpenAPI document")
+	overlayPath := flag.String("overlay", "openapi/overlay.json", "Go names for the document")
+	out := flag.String("out", ".", "directory to write the generated files to")
+	var extensions [][]byte
+	flag.Func("extension", "local OpenAPI document merged into -spec; may be repeated", func(path string) error {
+		data, err := os.ReadFile(path)
+		extensions = append(extensions, data)
+		return err
+	})
+	flag.Parse()
+
+	spec, err := os.ReadFile(*specPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	overlay, err := os.ReadFile(*overlayPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	files, err := openapigen.Generate(spec, overlay, extensions...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for name, source := range files {
+		if err := os.WriteFile(filepath.Join(*out, name), source, 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
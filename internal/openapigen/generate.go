@@ -0,0 +1,509 @@
+package openapigen
+
+import (
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+)
+
+const (
+	MODELS_FILE     = "models_gen.go"
+	VALIDATORS_FILE = "validators_gen.go"
+	SERVICES_FILE   = "services_gen.go"
+)
+
+// Generate returns the formatted source of the generated files by name.
+func Generate(specData, overlayData []byte, extensions ...[]byte) (map[string][]byte, error) {
+	api, err := Load(specData, overlayData, extensions...)
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string][]byte{}
+	for name, generate := range map[string]func() (*file, error){
+		MODELS_FILE:     api.models,
+		VALIDATORS_FILE: api.validators,
+		SERVICES_FILE:   api.services,
+	} {
+		f, err := generate()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		source, err := f.format(api.Overlay.Package)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		files[name] = source
+	}
+	return files, nil
+}
+
+type file struct {
+	imports map[string]bool
+	body    strings.Builder
+}
+
+func newFile() *file {
+	return &file{imports: map[string]bool{}}
+}
+
+func (f *file) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&f.body, format, args...)
+}
+
+func (f *file) format(pkg string) ([]byte, error) {
+	var source strings.Builder
+	source.WriteString("// Code generated by openapigen from openapi/unsend.json. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&source, "package %s\n\n", pkg)
+
+	if len(f.imports) > 0 {
+		imports := make([]string, 0, len(f.imports))
+		for path := range f.imports {
+			imports = append(imports, path)
+		}
+		sort.Strings(imports)
+		source.WriteString("import (\n")
+		for _, path := range imports {
+			fmt.Fprintf(&source, "%q\n", path)
+		}
+		source.WriteString(")\n\n")
+	}
+
+	source.WriteString(f.body.String())
+	formatted, err := format.Source([]byte(source.String()))
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w\n%s", err, source.String())
+	}
+	return formatted, nil
+}
+
+// GoType returns the Go type of a schema.
+func (a *API) GoType(schema *Schema) (string, error) {
+	if schema.Ref != "" {
+		return a.SchemaName(RefName(schema.Ref))
+	}
+
+	switch schema.Type {
+	case "string":
+		return "string", nil
+	case "integer":
+		return "int", nil
+	case "number":
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	case "array":
+		if schema.Items == nil {
+			return "[]interface{}", nil
+		}
+		item, err := a.GoType(schema.Items)
+		return "[]" + item, err
+	case "object":
+		if len(schema.Properties) > 0 {
+			return "", fmt.Errorf("inline object schemas need a component")
+		}
+		return "map[string]interface{}", nil
+	case "":
+		return "interface{}", nil
+	}
+	return "", fmt.Errorf("unsupported schema type %s", schema.Type)
+}
+
+// jsonTag omits empty optional fields. Booleans are always sent, so false
+// can be set explicitly, unless false is their default anyway.
+func jsonTag(name string, schema *Schema, required bool) string {
+	if required || (schema.Type == "boolean" && string(schema.Default) != "false") {
+		return fmt.Sprintf("`json:%q`", name)
+	}
+	return fmt.Sprintf("`json:%q`", name+",omitempty")
+}
+
+// responseSchemas returns the component schemas reachable from responses.
+// They get an Extra field for properties the spec doesn't describe.
+func (a *API) responseSchemas() map[string]bool {
+	reachable := map[string]bool{}
+	var walk func(schema *Schema)
+	walk = func(schema *Schema) {
+		if schema == nil {
+			return
+		}
+		if schema.Ref != "" {
+			name := RefName(schema.Ref)
+			if reachable[name] {
+				return
+			}
+			reachable[name] = true
+			schema = a.Spec.Components.Schemas[name]
+			if schema == nil {
+				return
+			}
+		}
+		walk(schema.Items)
+		for _, property := range schema.Properties {
+			walk(property.Schema)
+		}
+	}
+
+	for _, operation := range a.Operations {
+		walk(operation.Operation.ResponseSchema())
+	}
+	return reachable
+}
+
+func (a *API) models() (*file, error) {
+	f := newFile()
+
+	for _, operation := range a.Operations {
+		if operation.Mapping.Request == "" {
+			continue
+		}
+
+		body, err := a.Spec.Resolve(operation.Operation.RequestSchema())
+		if err != nil {
+			return nil, err
+		}
+
+		// Parameters are only kept out of the JSON of requests that are sent
+		// as a body, as they always have been.
+		paramTag := ""
+		if body != nil {
+			paramTag = " `json:\"-\"`"
+		}
+
+		f.printf("type %s struct {\n", operation.Mapping.Request)
+		for _, param := range append(operation.PathParams(), operation.QueryParams()...) {
+			paramType, err := a.GoType(param.Schema)
+			if err != nil {
+				return nil, fmt.Errorf("%s parameter %s: %w", operation.Mapping.OperationId, param.Name, err)
+			}
+			f.printf("%s %s%s\n", operation.ParamName(param.Name), paramType, paramTag)
+		}
+		if body != nil {
+			for _, property := range body.Properties {
+				propertyType, err := a.GoType(property.Schema)
+				if err != nil {
+					return nil, fmt.Errorf("%s property %s: %w", operation.Mapping.OperationId, property.Name, err)
+				}
+				f.printf("%s %s %s\n", exported(property.Name), propertyType, jsonTag(property.Name, property.Schema, body.IsRequired(property.Name)))
+			}
+		}
+
+		for _, field := range operation.Mapping.Fields {
+			if field.Doc != "" {
+				f.printf("\n// %s\n", field.Doc)
+			}
+			tag := "-"
+			if field.Json != "" {
+				tag = field.Json + ",omitempty"
+			}
+			f.printf("%s %s `json:%q`\n", field.Name, field.Type, tag)
+		}
+		f.printf("}\n\n")
+	}
+
+	responses := a.responseSchemas()
+	for _, mapping := range a.Overlay.Schemas {
+		schema := a.Spec.Components.Schemas[mapping.Schema]
+		if schema.Type == "string" {
+			f.printf("type %s string\n\n", mapping.Name)
+			if len(schema.Enum) > 0 {
+				f.printf("const (\n")
+				for _, value := range schema.Enum {
+					f.printf("%s%s %s = %q\n", mapping.ConstPrefix, value, mapping.Name, value)
+				}
+				f.printf(")\n\n")
+			}
+			continue
+		}
+
+		f.printf("type %s struct {\n", mapping.Name)
+		for _, property := range schema.Properties {
+			propertyType, err := a.GoType(property.Schema)
+			if err != nil {
+				return nil, fmt.Errorf("%s property %s: %w", mapping.Schema, property.Name, err)
+			}
+			f.printf("%s %s %s\n", a.FieldName(mapping.Schema, property.Name), propertyType, jsonTag(property.Name, property.Schema, schema.IsRequired(property.Name)))
+		}
+		if responses[mapping.Schema] && schema.Type == "object" {
+			f.imports["encoding/json"] = true
+			f.printf("Extra map[string]json.RawMessage `json:\"-\"`\n")
+		}
+		f.printf("}\n\n")
+	}
+
+	for _, mapping := range a.Overlay.Schemas {
+		if !responses[mapping.Schema] || a.Spec.Components.Schemas[mapping.Schema].Type != "object" {
+			continue
+		}
+		f.printf(`func (r *%[1]s) UnmarshalJSON(data []byte) error {
+	type plain %[1]s
+	return unmarshalWithExtra(data, (*plain)(r), &r.Extra)
+}
+
+func (r %[1]s) MarshalJSON() ([]byte, error) {
+	type plain %[1]s
+	return marshalWithExtra(plain(r), r.Extra)
+}
+
+`, mapping.Name)
+	}
+	return f, nil
+}
+
+// requiredCheck returns the condition under which a required field is
+// missing, or "" when the type has no zero value to check.
+func (a *API) requiredCheck(field string, schema *Schema) (string, error) {
+	schema, err := a.Spec.Resolve(schema)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case schema.Type == "string":
+		return fmt.Sprintf("req.%s == \"\"", field), nil
+	case schema.Type == "integer":
+		return fmt.Sprintf("req.%s <= 0", field), nil
+	case schema.Type == "array" || (schema.Type == "object" && len(schema.Properties) == 0):
+		return fmt.Sprintf("len(req.%s) == 0", field), nil
+	}
+	return "", nil
+}
+
+type requiredField struct {
+	name  string
+	check string
+}
+
+// requiredFields returns the path parameters and required body properties a
+// request must set, in the order they are validated.
+func (a *API) requiredFields(operation *APIOperation) ([]requiredField, error) {
+	var fields []requiredField
+	for _, param := range operation.PathParams() {
+		name := operation.ParamName(param.Name)
+		check, err := a.requiredCheck(name, param.Schema)
+		if err != nil {
+			return nil, err
+		}
+		if check != "" {
+			fields = append(fields, requiredField{name, check})
+		}
+	}
+
+	body, err := a.Spec.Resolve(operation.Operation.RequestSchema())
+	if err != nil || body == nil {
+		return fields, err
+	}
+	for _, property := range body.Properties {
+		if !body.IsRequired(property.Name) {
+			continue
+		}
+		name := exported(property.Name)
+		check, err := a.requiredCheck(name, property.Schema)
+		if err != nil {
+			return nil, err
+		}
+		if check != "" {
+			fields = append(fields, requiredField{name, check})
+		}
+	}
+	return fields, nil
+}
+
+func (a *API) validators() (*file, error) {
+	f := newFile()
+
+	for _, operation := range a.Operations {
+		if operation.Mapping.Request == "" {
+			continue
+		}
+
+		fields, err := a.requiredFields(operation)
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		f.printf("func (req %s) Validate() *ValidationError {\nerrors := new(ValidationError)\n", operation.Mapping.Request)
+		for _, field := range fields {
+			f.printf("if %s {\nerrors.Errors = append(errors.Errors, \"'%s' is required\")\n}\n\n", field.check, field.name)
+		}
+		f.printf("if len(errors.Errors) > 0 {\nreturn errors\n}\n\nreturn nil\n}\n\n")
+	}
+	return f, nil
+}
+
+// pathExpression builds the request path from literals and path parameters.
+func (a *API) pathExpression(f *file, operation *APIOperation) (string, error) {
+	path := strings.Trim(a.Overlay.PathPrefix, "/") + operation.Path
+	if operation.Mapping.TrailingSlash {
+		path += "/"
+	}
+
+	params := map[string]*Parameter{}
+	for _, param := range operation.PathParams() {
+		params[param.Name] = param
+	}
+
+	var parts []string
+	literal := ""
+	for len(path) > 0 {
+		start := strings.IndexByte(path, '{')
+		if start < 0 {
+			literal += path
+			break
+		}
+		end := strings.IndexByte(path[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated parameter in %s", operation.Path)
+		}
+		literal += path[:start]
+		name := path[start+1 : start+end]
+		path = path[start+end+1:]
+
+		param, ok := params[name]
+		if !ok {
+			return "", fmt.Errorf("%s has no parameter %s", operation.Mapping.OperationId, name)
+		}
+		if literal != "" {
+			parts = append(parts, fmt.Sprintf("%q", literal))
+			literal = ""
+		}
+
+		field := "request." + operation.ParamName(name)
+		switch param.Schema.Type {
+		case "integer":
+			f.imports["strconv"] = true
+			parts = append(parts, "strconv.Itoa("+field+")")
+		case "string":
+			parts = append(parts, field)
+		default:
+			return "", fmt.Errorf("unsupported path parameter type %s", param.Schema.Type)
+		}
+	}
+	if literal != "" {
+		parts = append(parts, fmt.Sprintf("%q", literal))
+	}
+	return strings.Join(parts, " + "), nil
+}
+
+// queryStatements adds the query parameters that are set to the path.
+func (a *API) queryStatements(f *file, operation *APIOperation) error {
+	params := operation.QueryParams()
+	if len(params) == 0 {
+		return nil
+	}
+
+	f.imports["net/url"] = true
+	f.printf("query := url.Values{}\n")
+	for _, param := range params {
+		field := "request." + operation.ParamName(param.Name)
+		schema, err := a.Spec.Resolve(param.Schema)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case schema.Type == "integer":
+			f.imports["strconv"] = true
+			f.printf("if %[1]s != 0 {\nquery.Set(%[2]q, strconv.Itoa(%[1]s))\n}\n", field, param.Name)
+		case schema.Type == "boolean":
+			f.imports["strconv"] = true
+			f.printf("if %[1]s {\nquery.Set(%[2]q, strconv.FormatBool(%[1]s))\n}\n", field, param.Name)
+		case schema.Type == "string" && param.Schema.Ref != "":
+			f.printf("if %[1]s != \"\" {\nquery.Set(%[2]q, string(%[1]s))\n}\n", field, param.Name)
+		case schema.Type == "string":
+			f.printf("if %[1]s != \"\" {\nquery.Set(%[2]q, %[1]s)\n}\n", field, param.Name)
+		default:
+			return fmt.Errorf("unsupported query parameter type %s", schema.Type)
+		}
+	}
+	f.printf("if len(query) > 0 {\npath += \"?\" + query.Encode()\n}\n\n")
+	return nil
+}
+
+func (a *API) services() (*file, error) {
+	f := newFile()
+	f.imports["context"] = true
+	f.imports["errors"] = true
+	f.imports["net/http"] = true
+
+	f.printf("// apiOperations lists the operations of the API by the SDK method that\n// calls them.\n")
+	f.printf("var apiOperations = []apiOperation{\n")
+	for _, operation := range a.Operations {
+		f.printf("{Method: %s, Path: %q, Name: %q},\n", operation.MethodConst(), operation.Path, operation.Mapping.Method)
+	}
+	f.printf("}\n\n")
+
+	for _, operation := range a.Operations {
+		mapping := operation.Mapping
+		receiver := operation.Service.Receiver
+
+		responseSchema := operation.Operation.ResponseSchema()
+		if responseSchema == nil {
+			return nil, fmt.Errorf("%s has no JSON response", mapping.OperationId)
+		}
+		responseType, err := a.GoType(responseSchema)
+		if err != nil {
+			return nil, err
+		}
+
+		params := "ctx context.Context"
+		if mapping.Request != "" {
+			params += ", request " + mapping.Request
+		}
+		f.printf("func (%s *%s) %s(%s) (*%s, error) {\n", receiver, operation.Service.Impl, mapping.Method, params, responseType)
+
+		for _, def := range mapping.Defaults {
+			f.printf("if request.%[1]s == \"\" {\nrequest.%[1]s = %[2]s.Client.%[3]s\n}\n\n", def.Field, receiver, def.Client)
+		}
+
+		fields, err := a.requiredFields(operation)
+		if err != nil {
+			return nil, err
+		}
+		if mapping.Request != "" && len(fields) > 0 {
+			f.imports["fmt"] = true
+			f.printf("if err := request.Validate(); err != nil {\nreturn nil, fmt.Errorf(\"[ERROR]: %s not valid; %%w\", err)\n}\n\n", mapping.Request)
+		}
+
+		if mapping.Prepare {
+			f.printf("if err := request.prepare(ctx, %s.Client); err != nil {\nreturn nil, err\n}\n\n", receiver)
+		}
+
+		path, err := a.pathExpression(f, operation)
+		if err != nil {
+			return nil, err
+		}
+		f.printf("path := %s\n\n", path)
+
+		if err := a.queryStatements(f, operation); err != nil {
+			return nil, err
+		}
+
+		body := "nil"
+		if operation.Operation.RequestSchema() != nil {
+			body = "request"
+		}
+		failed := "nil"
+		if mapping.EmptyResult {
+			failed = "&" + responseType + "{}"
+		}
+
+		f.printf("req, err := %s.Client.NewRequestWithContext(ctx, %s, path, %s)\n", receiver, operation.MethodConst(), body)
+		if mapping.RawRequestError {
+			f.printf("if err != nil {\nreturn %s, err\n}\n\n", failed)
+		} else {
+			name := mapping.RequestError
+			if name == "" {
+				name = mapping.Method
+			}
+			f.printf("if err != nil {\nreturn %s, errors.New(\"[ERROR]: Failed to create %s request\")\n}\n\n", failed, name)
+		}
+
+		f.printf("response := new(%s)\nerr = %s.Client.Execute(req, response)\nif err != nil {\nreturn %s, err\n}\n\nreturn response, nil\n}\n\n", responseType, receiver, failed)
+	}
+	return f, nil
+}
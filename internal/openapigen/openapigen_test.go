@@ -0,0 +1,174 @@
+package openapigen_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/QGeeDev/unsend-go/internal/openapigen"
+)
+
+const spec = `{
+	"paths": {
+		"/v1/things/{id}": {
+			"get": {
+				"operationId": "getThing",
+				"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}],
+				"responses": {"200": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Thing"}}}}}
+			}
+		},
+		"/v1/things": {
+			"get": {
+				"operationId": "listThings",
+				"parameters": [{"name": "status", "in": "query", "schema": {"$ref": "#/components/schemas/ThingStatus"}}],
+				"responses": {"200": {"content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Thing"}}}}}}
+			}
+		}
+	},
+	"components": {
+		"schemas": {
+			"ThingStatus": {"type": "string", "enum": ["NEW", "OLD"]},
+			"Thing": {
+				"type": "object",
+				"properties": {"name": {"type": "string"}, "id": {"type": "integer"}, "tags": {"type": "array", "items": {"type": "string"}}},
+				"required": ["name", "id"]
+			}
+		}
+	}
+}`
+
+func TestGenerate(t *testing.T) {
+	overlay := `{
+		"package": "things",
+		"pathPrefix": "api",
+		"schemas": [{"schema": "ThingStatus", "name": "ThingStatus", "constPrefix": "THING_STATUS_"}, {"schema": "Thing", "name": "ThingResponse"}],
+		"services": [{"name": "Things", "impl": "ThingsImpl", "receiver": "t", "operations": [
+			{"operationId": "getThing", "method": "GetThing", "request": "GetThingRequest", "params": {"id": "ThingId"}, "requestError": "Thing.Get", "emptyResult": true},
+			{"operationId": "listThings", "method": "ListThings", "request": "ListThingsRequest", "rawRequestError": true}
+		]}]
+	}`
+
+	files, err := openapigen.Generate([]byte(spec), []byte(overlay))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		file     string
+		expected string
+	}{
+		{openapigen.MODELS_FILE, "type GetThingRequest struct {\n\tThingId int\n}"},
+		{openapigen.MODELS_FILE, "Name  string                     `json:\"name\"`\n\tId    int"},
+		{openapigen.MODELS_FILE, "Tags  []string                   `json:\"tags,omitempty\"`"},
+		{openapigen.VALIDATORS_FILE, "if req.ThingId <= 0 {"},
+		{openapigen.SERVICES_FILE, `path := "api/v1/things/" + strconv.Itoa(request.ThingId)`},
+		{openapigen.MODELS_FILE, `THING_STATUS_NEW ThingStatus = "NEW"`},
+		{openapigen.MODELS_FILE, "type ListThingsRequest struct {\n\tStatus ThingStatus\n}"},
+		{openapigen.SERVICES_FILE, "return &ThingResponse{}, errors.New(\"[ERROR]: Failed to create Thing.Get request\")"},
+		{openapigen.SERVICES_FILE, "if err != nil {\n\t\treturn &ThingResponse{}, err\n\t}"},
+		{openapigen.SERVICES_FILE, "req, err := t.Client.NewRequestWithContext(ctx, http.MethodGet, path, nil)\n\tif err != nil {\n\t\treturn nil, err\n\t}"},
+		{openapigen.SERVICES_FILE, `{Method: http.MethodGet, Path: "/v1/things/{id}", Name: "GetThing"}`},
+		{openapigen.SERVICES_FILE, `query.Set("status", string(request.Status))`},
+		{openapigen.SERVICES_FILE, "func (t *ThingsImpl) ListThings(ctx context.Context, request ListThingsRequest) (*[]ThingResponse, error)"},
+	}
+	for _, tt := range tests {
+		if !strings.Contains(string(files[tt.file]), tt.expected) {
+			t.Errorf("expected %s to contain %q, got\n%s", tt.file, tt.expected, files[tt.file])
+		}
+	}
+}
+
+func TestLoadRejectsUnmappedOperations(t *testing.T) {
+	overlay := `{"package": "things", "schemas": [{"schema": "Thing", "name": "ThingResponse"}], "services": []}`
+
+	_, err := openapigen.Load([]byte(spec), []byte(overlay))
+	if err == nil || !strings.Contains(err.Error(), "has no SDK method") {
+		t.Errorf("expected an error for the unmapped operations, got %v", err)
+	}
+}
+
+func TestLoadExtensions(t *testing.T) {
+	overlay := `{"package": "things", "schemas": [{"schema": "Thing", "name": "ThingResponse"}], "services": [{"name": "Things", "operations": [
+		{"operationId": "getThing", "method": "GetThing"},
+		{"operationId": "listThings", "method": "ListThings"},
+		{"operationId": "archiveThing", "method": "ArchiveThing"}
+	]}]}`
+	extension := `{"paths": {"/v1/things/{id}/archive": {"post": {"operationId": "archiveThing"}}}}`
+
+	api, err := openapigen.Load([]byte(spec), []byte(overlay), []byte(extension))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if api.Match("POST", "/v1/things/1/archive") == nil {
+		t.Errorf("expected the extension's operation to be loaded")
+	}
+
+	redefined := `{"components": {"schemas": {"Thing": {"type": "object"}}}}`
+	if _, err := openapigen.Load([]byte(spec), []byte(overlay), []byte(extension), []byte(redefined)); err == nil ||
+		err.Error() != "extension redefines schema Thing" {
+		t.Errorf("expected an error for the redefined schema, got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	document, err := openapigen.ParseSpec([]byte(`{
+		"components": {
+			"schemas": {
+				"Email": {
+					"type": "object",
+					"properties": {
+						"to": {"type": "array", "items": {"type": "string", "format": "email"}},
+						"status": {"type": "string", "enum": ["SENT", "DELIVERED"]},
+						"scheduledAt": {"type": "string", "format": "date-time"},
+						"attempts": {"type": "integer"},
+						"tags": {"type": "object", "additionalProperties": {"type": "string"}},
+						"data": {}
+					},
+					"required": ["to"]
+				}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	schema := &openapigen.Schema{Ref: "#/components/schemas/Email"}
+
+	tests := []struct {
+		name     string
+		body     string
+		expected []string
+	}{
+		{"Valid", `{"to": ["Alice <a@b.c>"], "status": "SENT", "scheduledAt": "2024-06-01T09:00:00Z", "attempts": 2, "tags": {"a": "b"}, "data": [1]}`, nil},
+		{"Missing required", `{}`, []string{`$: missing required property "to"`}},
+		{"Unknown property", `{"to": [], "scheduleAt": "x", "tags": {"b": "c"}}`, []string{`$: unknown property "scheduleAt"`}},
+		{"Wrong types", `{"to": "a@b.c", "attempts": 1.5, "tags": {"a": 1}}`, []string{
+			"$.attempts: expected integer, got 1.5",
+			"$.tags.a: expected string, got number",
+			"$.to: expected array, got string",
+		}},
+		{"Formats and enums", `{"to": ["nobody"], "status": "LOST", "scheduledAt": "tomorrow"}`, []string{
+			`$.scheduledAt: "tomorrow" is not an RFC 3339 date-time`,
+			`$.status: "LOST" is not one of SENT, DELIVERED`,
+			`$.to[0]: "nobody" is not an email address`,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := document.ValidateStrict(schema, []byte(tt.body))
+			if tt.expected == nil {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != strings.Join(tt.expected, "\n") {
+				t.Errorf("expected %q, got %v", strings.Join(tt.expected, "\n"), err)
+			}
+		})
+	}
+
+	if err := document.Validate(schema, []byte(`{"to": [], "scheduleAt": "x"}`)); err != nil {
+		t.Errorf("expected unknown properties to be allowed outside strict mode, got %v", err)
+	}
+}
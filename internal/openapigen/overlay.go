@@ -0,0 +1,225 @@
+package openapigen
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Overlay maps the spec onto Go names. Schemas are generated in the order
+// they are listed, and services and operations in theirs.
+type Overlay struct {
+	Package    string          `json:"package"`
+	PathPrefix string          `json:"pathPrefix"`
+	Schemas    []SchemaMapping `json:"schemas"`
+	Services   []Service       `json:"services"`
+}
+
+type SchemaMapping struct {
+	Schema string `json:"schema"`
+	Name   string `json:"name"`
+	// Fields renames properties whose Go name isn't the property name with
+	// its first letter upper-cased.
+	Fields map[string]string `json:"fields"`
+	// ConstPrefix names the constants generated for the values of a string
+	// enum, such as CAMPAIGN_STATUS_ for CAMPAIGN_STATUS_DRAFT.
+	ConstPrefix string `json:"constPrefix"`
+}
+
+type Service struct {
+	Name       string             `json:"name"`
+	Impl       string             `json:"impl"`
+	Receiver   string             `json:"receiver"`
+	Operations []OperationMapping `json:"operations"`
+}
+
+type OperationMapping struct {
+	OperationId string `json:"operationId"`
+	Method      string `json:"method"`
+	// Request is the Go type holding the parameters and body. Operations
+	// without one take only a context.
+	Request string `json:"request"`
+	// Params renames path and query parameters.
+	Params map[string]string `json:"params"`
+	// Defaults fill empty request fields from fields of the Client.
+	Defaults []struct {
+		Field  string `json:"field"`
+		Client string `json:"client"`
+	} `json:"defaults"`
+	// Prepare calls the hand-written request.prepare(ctx, client) after
+	// validation.
+	Prepare bool `json:"prepare"`
+	// TrailingSlash keeps a trailing slash the API has always been called with.
+	TrailingSlash bool `json:"trailingSlash"`
+	// RequestError names the call in the error returned when the request
+	// can't be built, such as "Contact.Get" in "Failed to create Contact.Get
+	// request". It defaults to the method. RawRequestError returns the error
+	// itself instead.
+	RequestError    string `json:"requestError"`
+	RawRequestError bool   `json:"rawRequestError"`
+	// EmptyResult returns an empty response instead of nil when the request
+	// can't be built or sent, as the contact methods always have.
+	EmptyResult bool `json:"emptyResult"`
+	// Fields are extra fields added to the request type. They are left out
+	// of its JSON unless they name a JSON key.
+	Fields []struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+		Json string `json:"json"`
+		Doc  string `json:"doc"`
+	} `json:"fields"`
+}
+
+// API is the spec joined with the overlay.
+type API struct {
+	Spec       *Spec
+	Overlay    *Overlay
+	Operations []*APIOperation
+
+	names map[string]*SchemaMapping
+}
+
+// APIOperation is an operation of the spec and the SDK method that calls it.
+type APIOperation struct {
+	Service    *Service
+	Mapping    *OperationMapping
+	HTTPMethod string
+	Path       string
+	Operation  *Operation
+}
+
+// Load joins a spec, its extensions and an overlay, checking that every
+// operation of the spec is mapped and every mapping names an operation.
+func Load(specData, overlayData []byte, extensions ...[]byte) (*API, error) {
+	spec, err := ParseSpec(specData)
+	if err != nil {
+		return nil, err
+	}
+	for _, data := range extensions {
+		extension, err := ParseSpec(data)
+		if err != nil {
+			return nil, err
+		}
+		if err := spec.Extend(extension); err != nil {
+			return nil, err
+		}
+	}
+
+	overlay := new(Overlay)
+	if err := json.Unmarshal(overlayData, overlay); err != nil {
+		return nil, fmt.Errorf("parsing overlay: %w", err)
+	}
+
+	api := &API{Spec: spec, Overlay: overlay, names: map[string]*SchemaMapping{}}
+	for i := range overlay.Schemas {
+		mapping := &overlay.Schemas[i]
+		if _, ok := spec.Components.Schemas[mapping.Schema]; !ok {
+			return nil, fmt.Errorf("overlay maps unknown schema %s", mapping.Schema)
+		}
+		api.names[mapping.Schema] = mapping
+	}
+
+	mapped := map[string]bool{}
+	for i := range overlay.Services {
+		service := &overlay.Services[i]
+		for j := range service.Operations {
+			mapping := &service.Operations[j]
+			method, path, operation := spec.Find(mapping.OperationId)
+			if operation == nil {
+				return nil, fmt.Errorf("overlay maps unknown operation %s", mapping.OperationId)
+			}
+			mapped[mapping.OperationId] = true
+			api.Operations = append(api.Operations, &APIOperation{
+				Service:    service,
+				Mapping:    mapping,
+				HTTPMethod: method,
+				Path:       path,
+				Operation:  operation,
+			})
+		}
+	}
+
+	for path, methods := range spec.Paths {
+		for method, operation := range methods {
+			if !mapped[operation.OperationId] {
+				return nil, fmt.Errorf("%s %s (%s) has no SDK method in the overlay", strings.ToUpper(method), path, operation.OperationId)
+			}
+		}
+	}
+	return api, nil
+}
+
+// SchemaName returns the Go type of a component schema.
+func (a *API) SchemaName(component string) (string, error) {
+	mapping, ok := a.names[component]
+	if !ok {
+		return "", fmt.Errorf("schema %s has no Go name in the overlay", component)
+	}
+	return mapping.Name, nil
+}
+
+// FieldName returns the Go field of a property of a component schema, or of
+// a request body when component is empty.
+func (a *API) FieldName(component, property string) string {
+	if mapping, ok := a.names[component]; ok {
+		if name, ok := mapping.Fields[property]; ok {
+			return name
+		}
+	}
+	return exported(property)
+}
+
+// ParamName returns the Go field of a path or query parameter.
+func (o *APIOperation) ParamName(name string) string {
+	if field, ok := o.Mapping.Params[name]; ok {
+		return field
+	}
+	return exported(name)
+}
+
+// PathParams returns the path parameters of the operation in path order.
+func (o *APIOperation) PathParams() []*Parameter {
+	var params []*Parameter
+	for _, parameter := range o.Operation.Parameters {
+		if parameter.In == "path" {
+			params = append(params, parameter)
+		}
+	}
+	return params
+}
+
+// QueryParams returns the query parameters of the operation.
+func (o *APIOperation) QueryParams() []*Parameter {
+	var params []*Parameter
+	for _, parameter := range o.Operation.Parameters {
+		if parameter.In == "query" {
+			params = append(params, parameter)
+		}
+	}
+	return params
+}
+
+// MethodConst returns the net/http constant for the operation's method.
+func (o *APIOperation) MethodConst() string {
+	switch o.HTTPMethod {
+	case http.MethodGet:
+		return "http.MethodGet"
+	case http.MethodPost:
+		return "http.MethodPost"
+	case http.MethodPut:
+		return "http.MethodPut"
+	case http.MethodPatch:
+		return "http.MethodPatch"
+	case http.MethodDelete:
+		return "http.MethodDelete"
+	}
+	return fmt.Sprintf("%q", o.HTTPMethod)
+}
+
+func exported(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
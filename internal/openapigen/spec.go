@@ -0,0 +1,185 @@
+// Package openapigen generates the SDK's request and response types,
+// validators and service methods from the vendored OpenAPI document in
+// openapi/unsend.json. openapi/overlay.json maps the spec onto the SDK's Go
+// names so the generated API stays compatible with earlier releases.
+package openapigen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Spec is the subset of an OpenAPI 3 document the generator reads.
+type Spec struct {
+	Info struct {
+		Title   string `json:"title"`
+		Version string `json:"version"`
+	} `json:"info"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+}
+
+type Operation struct {
+	OperationId string       `json:"operationId"`
+	Parameters  []*Parameter `json:"parameters"`
+	RequestBody *struct {
+		Required bool                  `json:"required"`
+		Content  map[string]*MediaType `json:"content"`
+	} `json:"requestBody"`
+	Responses map[string]*struct {
+		Description string                `json:"description"`
+		Content     map[string]*MediaType `json:"content"`
+	} `json:"responses"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema is the subset of JSON Schema used by the spec. Properties keep the
+// order of the document, which is the order of the generated struct fields.
+type Schema struct {
+	Ref                  string          `json:"$ref"`
+	Type                 string          `json:"type"`
+	Format               string          `json:"format"`
+	Enum                 []string        `json:"enum"`
+	Items                *Schema         `json:"items"`
+	Properties           Properties      `json:"properties"`
+	Required             []string        `json:"required"`
+	AdditionalProperties json.RawMessage `json:"additionalProperties"`
+	Default              json.RawMessage `json:"default"`
+}
+
+type Property struct {
+	Name   string
+	Schema *Schema
+}
+
+type Properties []Property
+
+func (p *Properties) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return fmt.Errorf("properties must be an object")
+	}
+
+	*p = nil
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		property := Property{Name: token.(string), Schema: new(Schema)}
+		if err := decoder.Decode(property.Schema); err != nil {
+			return fmt.Errorf("property %s: %w", property.Name, err)
+		}
+		*p = append(*p, property)
+	}
+	return nil
+}
+
+// IsRequired reports whether the schema lists name as required.
+func (s *Schema) IsRequired(name string) bool {
+	for _, required := range s.Required {
+		if required == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseSpec decodes an OpenAPI document.
+func ParseSpec(data []byte) (*Spec, error) {
+	spec := new(Spec)
+	if err := json.Unmarshal(data, spec); err != nil {
+		return nil, fmt.Errorf("parsing spec: %w", err)
+	}
+	return spec, nil
+}
+
+// Extend adds the paths and component schemas of other, a local document for
+// endpoints the spec doesn't describe yet. Redefining a path or schema of the
+// spec is an error, so an extension has to be removed once upstream has it.
+func (s *Spec) Extend(other *Spec) error {
+	for path, methods := range other.Paths {
+		if _, ok := s.Paths[path]; ok {
+			return fmt.Errorf("extension redefines path %s", path)
+		}
+		if s.Paths == nil {
+			s.Paths = map[string]map[string]*Operation{}
+		}
+		s.Paths[path] = methods
+	}
+	for name, schema := range other.Components.Schemas {
+		if _, ok := s.Components.Schemas[name]; ok {
+			return fmt.Errorf("extension redefines schema %s", name)
+		}
+		if s.Components.Schemas == nil {
+			s.Components.Schemas = map[string]*Schema{}
+		}
+		s.Components.Schemas[name] = schema
+	}
+	return nil
+}
+
+// Resolve follows a local $ref to the component schema it names.
+func (s *Spec) Resolve(schema *Schema) (*Schema, error) {
+	if schema == nil || schema.Ref == "" {
+		return schema, nil
+	}
+
+	name, ok := strings.CutPrefix(schema.Ref, "#/components/schemas/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported reference %s", schema.Ref)
+	}
+	resolved, ok := s.Components.Schemas[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown schema %s", schema.Ref)
+	}
+	return resolved, nil
+}
+
+// RefName returns the component name of a $ref.
+func RefName(ref string) string {
+	return strings.TrimPrefix(ref, "#/components/schemas/")
+}
+
+// RequestSchema returns the JSON body schema of an operation, or nil.
+func (o *Operation) RequestSchema() *Schema {
+	if o.RequestBody == nil || o.RequestBody.Content["application/json"] == nil {
+		return nil
+	}
+	return o.RequestBody.Content["application/json"].Schema
+}
+
+// ResponseSchema returns the schema of the 200 response, or nil.
+func (o *Operation) ResponseSchema() *Schema {
+	response := o.Responses["200"]
+	if response == nil || response.Content["application/json"] == nil {
+		return nil
+	}
+	return response.Content["application/json"].Schema
+}
+
+// Find returns the operation with operationId and its method and path.
+func (s *Spec) Find(operationId string) (method, path string, operation *Operation) {
+	for path, methods := range s.Paths {
+		for method, operation := range methods {
+			if operation.OperationId == operationId {
+				return strings.ToUpper(method), path, operation
+			}
+		}
+	}
+	return "", "", nil
+}
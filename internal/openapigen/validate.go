@@ -0,0 +1,204 @@
+package openapigen
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Validate checks a JSON document against a schema of the spec. It supports
+// the keywords the spec uses: $ref, type, enum, format (date-time and email),
+// properties, required, items and additionalProperties. Every violation is
+// reported, each prefixed with the JSON path of the value.
+func (s *Spec) Validate(schema *Schema, data []byte) error {
+	return s.validateDocument(schema, data, false)
+}
+
+// ValidateStrict is Validate with objects that list their properties closed
+// unless they say otherwise, so a request with a misspelled field fails. The
+// spec leaves additionalProperties unset on request bodies, which allows
+// anything.
+func (s *Spec) ValidateStrict(schema *Schema, data []byte) error {
+	return s.validateDocument(schema, data, true)
+}
+
+func (s *Spec) validateDocument(schema *Schema, data []byte, strict bool) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	var violations []error
+	s.validate(schema, value, "$", strict, &violations)
+	return errors.Join(violations...)
+}
+
+func (s *Spec) validate(schema *Schema, value interface{}, path string, strict bool, violations *[]error) {
+	fail := func(format string, args ...interface{}) {
+		*violations = append(*violations, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...)))
+	}
+
+	schema, err := s.Resolve(schema)
+	if err != nil {
+		fail("%v", err)
+		return
+	}
+	if schema == nil {
+		return
+	}
+
+	switch schema.Type {
+	case "":
+		return
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			fail("expected string, got %s", jsonType(value))
+			return
+		}
+		if len(schema.Enum) > 0 && !contains(schema.Enum, text) {
+			fail("%q is not one of %s", text, strings.Join(schema.Enum, ", "))
+		}
+		switch schema.Format {
+		case "date-time":
+			if _, err := time.Parse(time.RFC3339, text); err != nil {
+				fail("%q is not an RFC 3339 date-time", text)
+			}
+		case "email":
+			if _, err := mail.ParseAddress(text); err != nil {
+				fail("%q is not an email address", text)
+			}
+		}
+	case "integer", "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			fail("expected %s, got %s", schema.Type, jsonType(value))
+			return
+		}
+		if _, err := number.Int64(); schema.Type == "integer" && err != nil {
+			fail("expected integer, got %s", number)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("expected boolean, got %s", jsonType(value))
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			fail("expected array, got %s", jsonType(value))
+			return
+		}
+		for i, item := range items {
+			s.validate(schema.Items, item, fmt.Sprintf("%s[%d]", path, i), strict, violations)
+		}
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			fail("expected object, got %s", jsonType(value))
+			return
+		}
+		for _, required := range schema.Required {
+			if _, ok := object[required]; !ok {
+				fail("missing required property %q", required)
+			}
+		}
+
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if property := schema.property(key); property != nil {
+				s.validate(property, object[key], path+"."+key, strict, violations)
+				continue
+			}
+			switch additional := bytes.TrimSpace(schema.AdditionalProperties); {
+			case string(additional) == "false", len(additional) == 0 && strict && len(schema.Properties) > 0:
+				fail("unknown property %q", key)
+			case len(additional) > 0 && string(additional) != "true":
+				var properties Schema
+				if err := json.Unmarshal(additional, &properties); err != nil {
+					fail("invalid additionalProperties: %v", err)
+					return
+				}
+				s.validate(&properties, object[key], path+"."+key, strict, violations)
+			}
+		}
+	default:
+		fail("unsupported schema type %s", schema.Type)
+	}
+}
+
+func (s *Schema) property(name string) *Schema {
+	for _, property := range s.Properties {
+		if property.Name == name {
+			return property.Schema
+		}
+	}
+	return nil
+}
+
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	}
+	return "object"
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Match returns the operation a request with method and path, such as
+// /api/v1/emails/123, is an instance of, or nil.
+func (a *API) Match(method, path string) *APIOperation {
+	path = strings.TrimPrefix(path, "/"+strings.Trim(a.Overlay.PathPrefix, "/"))
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	for _, operation := range a.Operations {
+		if operation.HTTPMethod != method {
+			continue
+		}
+		template := strings.Split(strings.Trim(operation.Path, "/"), "/")
+		if len(template) != len(segments) {
+			continue
+		}
+
+		matched := true
+		for i, segment := range template {
+			isParam := strings.HasPrefix(segment, "{")
+			if (isParam && segments[i] == "") || (!isParam && segment != segments[i]) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return operation
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package unsend
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const REDACTED = "[REDACTED]"
+
+// DEFAULT_REDACT_FIELDS are JSON keys whose values are never logged. Matching
+// is case-insensitive and applies at any depth.
+var DEFAULT_REDACT_FIELDS = []string{
+	"to", "from", "cc", "bcc", "replyTo",
+	"subject", "html", "text", "variables",
+	"email", "firstName", "lastName",
+	"apiKey", "api_key", "token", "password",
+}
+
+var redactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// logExchange records one HTTP attempt. Completed requests are logged at info,
+// failures at warn, and the redacted headers and bodies only at debug.
+func (c *Client) logExchange(req *http.Request, resp *http.Response, respBody []byte, latency time.Duration, attempt int, err error) {
+	if c.Logger == nil {
+		return
+	}
+
+	ctx := req.Context()
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+		slog.Duration("latency", latency),
+		slog.Int("attempt", attempt),
+	}
+
+	level := slog.LevelInfo
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			level = slog.LevelWarn
+		}
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		level = slog.LevelWarn
+	}
+
+	if c.Logger.Enabled(ctx, slog.LevelDebug) {
+		attrs = append(attrs,
+			slog.Any("request_headers", redactHeaders(req.Header)),
+			slog.String("request_body", c.redactBody(requestBody(req))),
+		)
+		if resp != nil {
+			attrs = append(attrs,
+				slog.Any("response_headers", redactHeaders(resp.Header)),
+				slog.String("response_body", c.redactBody(respBody)),
+			)
+		}
+	}
+
+	c.Logger.LogAttrs(ctx, level, "unsend request", attrs...)
+}
+
+func (c *Client) redactBody(body []byte) string {
+	fields := make([]string, 0, len(DEFAULT_REDACT_FIELDS)+len(c.RedactFields))
+	fields = append(fields, DEFAULT_REDACT_FIELDS...)
+	fields = append(fields, c.RedactFields...)
+	return string(RedactJSON(body, fields))
+}
+
+// RedactJSON replaces the values of the given keys anywhere in a JSON
+// document. Bodies that aren't JSON are replaced entirely.
+func RedactJSON(body []byte, fields []string) []byte {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return body
+	}
+
+	var document interface{}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return []byte(REDACTED)
+	}
+
+	redact := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		redact[strings.ToLower(field)] = true
+	}
+
+	redacted, err := json.Marshal(redactValue(document, redact))
+	if err != nil {
+		return []byte(REDACTED)
+	}
+	return redacted
+}
+
+func redactValue(value interface{}, redact map[string]bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, inner := range v {
+			if redact[strings.ToLower(key)] {
+				v[key] = REDACTED
+			} else {
+				v[key] = redactValue(inner, redact)
+			}
+		}
+	case []interface{}:
+		for i, inner := range v {
+			v[i] = redactValue(inner, redact)
+		}
+	}
+	return value
+}
+
+func redactHeaders(header http.Header) http.Header {
+	redacted := header.Clone()
+	for _, name := range redactedHeaders {
+		if redacted.Get(name) != "" {
+			redacted.Set(name, REDACTED)
+		}
+	}
+	return redacted
+}
+
+func requestBody(req *http.Request) []byte {
+	if req.GetBody == nil {
+		return nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+
+	content, _ := io.ReadAll(body)
+	return content
+}
@@ -0,0 +1,154 @@
+package unsend_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+func TestRequestLogging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/emails" && r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"emailId": "12345"}`))
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "not found", "email": "jane@example.com"}`))
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		level         slog.Level
+		expectBodies  bool
+		expectedLevel string
+		send          func(client *unsend.Client) error
+	}{
+		{
+			name:          "Info level omits bodies",
+			level:         slog.LevelInfo,
+			expectBodies:  false,
+			expectedLevel: "INFO",
+			send: func(client *unsend.Client) error {
+				_, err := client.Emails.SendEmail(context.Background(), unsend.SendEmailRequest{
+					To:      []string{"jane@example.com"},
+					From:    "hello@unsend.dev",
+					Subject: "Hello Jane",
+				})
+				return err
+			},
+		},
+		{
+			name:          "Debug level logs redacted bodies",
+			level:         slog.LevelDebug,
+			expectBodies:  true,
+			expectedLevel: "INFO",
+			send: func(client *unsend.Client) error {
+				_, err := client.Emails.SendEmail(context.Background(), unsend.SendEmailRequest{
+					To:      []string{"jane@example.com"},
+					From:    "hello@unsend.dev",
+					Subject: "Hello Jane",
+				})
+				return err
+			},
+		},
+		{
+			name:          "Failures log at warn",
+			level:         slog.LevelDebug,
+			expectBodies:  true,
+			expectedLevel: "WARN",
+			send: func(client *unsend.Client) error {
+				_, err := client.Emails.GetEmail(context.Background(), unsend.GetEmailRequest{EmailId: "missing"})
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			client := &unsend.Client{
+				Client:       &http.Client{Transport: &unsend.UnsendTransport{ApiKey: "secret-key"}},
+				Logger:       slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: tt.level})),
+				RedactFields: []string{"subject"},
+			}
+			client.BaseUrl, _ = url.Parse(server.URL)
+			client.Emails = &unsend.EmailsImpl{Client: client}
+
+			tt.send(client)
+
+			var entry map[string]interface{}
+			if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+				t.Fatalf("expected one JSON log entry, got %q", logs.String())
+			}
+
+			if entry["level"] != tt.expectedLevel {
+				t.Errorf("expected level %s, got %v", tt.expectedLevel, entry["level"])
+			}
+			for _, key := range []string{"method", "path", "status", "latency", "attempt"} {
+				if _, ok := entry[key]; !ok {
+					t.Errorf("expected %s to be logged, got %v", key, entry)
+				}
+			}
+
+			_, hasBody := entry["request_body"]
+			if hasBody != tt.expectBodies {
+				t.Errorf("expected bodies logged to be %v, got %v", tt.expectBodies, hasBody)
+			}
+
+			for _, secret := range []string{"secret-key", "jane@example.com", "Hello Jane", "hello@unsend.dev"} {
+				if strings.Contains(logs.String(), secret) {
+					t.Errorf("expected %q to be redacted, got %s", secret, logs.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRedactJSON(t *testing.T) {
+	body := []byte(`{"to": ["a@b.c"], "subject": "Reset", "html": "<p>Code 1234</p>", "variables": {"code": "1234"}, "contact": {"Email": "a@b.c", "plan": "pro"}, "items": [{"token": "x"}]}`)
+
+	redacted := unsend.RedactJSON(body, unsend.DEFAULT_REDACT_FIELDS)
+
+	expected := `{"contact":{"Email":"[REDACTED]","plan":"pro"},"html":"[REDACTED]","items":[{"token":"[REDACTED]"}],"subject":"[REDACTED]","to":"[REDACTED]","variables":"[REDACTED]"}`
+	if string(redacted) != expected {
+		t.Errorf("expected %s, got %s", expected, redacted)
+	}
+
+	if got := string(unsend.RedactJSON([]byte("not json a@b.c"), nil)); got != unsend.REDACTED {
+		t.Errorf("expected non-JSON body to be fully redacted, got %s", got)
+	}
+}
+
+func TestGetEnvOrDefaultDoesNotWriteToStdout(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	value := unsend.GetEnvOrDefault("UNSEND_TEST_UNSET_VARIABLE", "fallback")
+
+	writer.Close()
+	output, _ := io.ReadAll(reader)
+
+	if value != "fallback" {
+		t.Errorf("expected fallback, got %s", value)
+	}
+	if len(output) != 0 {
+		t.Errorf("expected nothing on stdout, got %q", output)
+	}
+}
@@ -0,0 +1,259 @@
+package unsend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const STATUS_CLASS_ERROR = "error"
+
+// DEFAULT_METRICS_BUCKETS are the request duration histogram buckets, in
+// seconds.
+var DEFAULT_METRICS_BUCKETS = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// RequestMetrics describes one API call, including any retries.
+type RequestMetrics struct {
+	// Service is the endpoint group (ENDPOINT_GROUP_EMAILS, ...) and Operation
+	// the SDK method, such as "SendEmail".
+	Service   string
+	Operation string
+	// StatusClass is "2xx", "4xx", "5xx" and so on, or STATUS_CLASS_ERROR when
+	// no response was received.
+	StatusClass string
+	StatusCode  int
+	Duration    time.Duration
+	Retries     int
+}
+
+// Metrics is called once per API call the Client makes. Requests answered by
+// the sandbox or the idempotency cache are not observed.
+type Metrics interface {
+	ObserveRequest(ctx context.Context, metrics RequestMetrics)
+}
+
+// observeRequest reports a finished call to c.Metrics.
+func (c *Client) observeRequest(req *http.Request, exchange *exchange, duration time.Duration, err error) {
+	if c.Metrics == nil {
+		return
+	}
+
+	metrics := RequestMetrics{
+		Service:     endpointGroup(req.URL.Path),
+		Operation:   endpointOperation(req.Method, req.URL.Path),
+		StatusClass: STATUS_CLASS_ERROR,
+		Duration:    duration,
+		Retries:     max(exchange.attempts-1, 0),
+	}
+
+	var apiErr *APIError
+	switch {
+	case err == nil && exchange.response != nil:
+		metrics.StatusCode = exchange.response.StatusCode
+	case errors.As(err, &apiErr):
+		metrics.StatusCode = apiErr.StatusCode
+	}
+	if metrics.StatusCode > 0 {
+		metrics.StatusClass = strconv.Itoa(metrics.StatusCode/100) + "xx"
+	}
+
+	c.Metrics.ObserveRequest(req.Context(), metrics)
+}
+
+// apiOperation is an operation of the API, with its path relative to /api as
+// in the OpenAPI document, and the SDK method that calls it.
+type apiOperation struct {
+	Method string
+	Path   string
+	Name   string
+}
+
+// matches reports whether a request path such as /api/v1/emails/123 is an
+// instance of the operation's templated path.
+func (o apiOperation) matches(method, path string) bool {
+	if method != o.Method {
+		return false
+	}
+
+	_, rest, _ := strings.Cut(path, "v1/")
+	_, template, _ := strings.Cut(o.Path, "v1/")
+	segments := strings.Split(strings.Trim(rest, "/"), "/")
+	templateSegments := strings.Split(strings.Trim(template, "/"), "/")
+	if len(segments) != len(templateSegments) {
+		return false
+	}
+
+	for i, segment := range templateSegments {
+		if strings.HasPrefix(segment, "{") {
+			if segments[i] == "" {
+				return false
+			}
+		} else if segment != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// endpointOperation names the SDK method that makes a request, so metrics and
+// traces don't depend on ids in the path.
+func endpointOperation(method, path string) string {
+	for _, operation := range apiOperations {
+		if operation.matches(method, path) {
+			return operation.Name
+		}
+	}
+	return "unknown"
+}
+
+// PrometheusMetrics keeps request counters and latency histograms in memory
+// and serves them in the Prometheus text format:
+//
+//	http.Handle("/metrics", metrics)
+type PrometheusMetrics struct {
+	buckets []float64
+
+	mu        sync.Mutex
+	requests  map[metricLabels]float64
+	retries   map[metricLabels]float64
+	durations map[metricLabels]*histogram
+}
+
+type metricLabels struct {
+	service     string
+	operation   string
+	statusClass string
+}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// NewPrometheusMetrics uses DEFAULT_METRICS_BUCKETS when no buckets are given.
+func NewPrometheusMetrics(buckets ...float64) *PrometheusMetrics {
+	if len(buckets) == 0 {
+		buckets = DEFAULT_METRICS_BUCKETS
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	return &PrometheusMetrics{
+		buckets:   buckets,
+		requests:  map[metricLabels]float64{},
+		retries:   map[metricLabels]float64{},
+		durations: map[metricLabels]*histogram{},
+	}
+}
+
+func (m *PrometheusMetrics) ObserveRequest(ctx context.Context, metrics RequestMetrics) {
+	labels := metricLabels{service: metrics.Service, operation: metrics.Operation}
+	seconds := metrics.Duration.Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.retries[labels] += float64(metrics.Retries)
+
+	h := m.durations[labels]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.durations[labels] = h
+	}
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+
+	labels.statusClass = metrics.StatusClass
+	m.requests[labels]++
+}
+
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write([]byte(m.String()))
+}
+
+// String renders the metrics in the Prometheus text format.
+func (m *PrometheusMetrics) String() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+
+	b.WriteString("# HELP unsend_requests_total Unsend API calls by outcome.\n")
+	b.WriteString("# TYPE unsend_requests_total counter\n")
+	for _, labels := range sortedLabels(m.requests) {
+		fmt.Fprintf(&b, "unsend_requests_total%s %s\n", labels.format(true, ""), formatFloat(m.requests[labels]))
+	}
+
+	b.WriteString("# HELP unsend_request_retries_total Retried Unsend API attempts.\n")
+	b.WriteString("# TYPE unsend_request_retries_total counter\n")
+	for _, labels := range sortedLabels(m.retries) {
+		fmt.Fprintf(&b, "unsend_request_retries_total%s %s\n", labels.format(false, ""), formatFloat(m.retries[labels]))
+	}
+
+	b.WriteString("# HELP unsend_request_duration_seconds Unsend API call latency, including retries.\n")
+	b.WriteString("# TYPE unsend_request_duration_seconds histogram\n")
+	for _, labels := range sortedLabels(m.durations) {
+		h := m.durations[labels]
+		for i, bound := range m.buckets {
+			fmt.Fprintf(&b, "unsend_request_duration_seconds_bucket%s %d\n", labels.format(false, formatFloat(bound)), h.counts[i])
+		}
+		fmt.Fprintf(&b, "unsend_request_duration_seconds_bucket%s %d\n", labels.format(false, "+Inf"), h.count)
+		fmt.Fprintf(&b, "unsend_request_duration_seconds_sum%s %s\n", labels.format(false, ""), formatFloat(h.sum))
+		fmt.Fprintf(&b, "unsend_request_duration_seconds_count%s %d\n", labels.format(false, ""), h.count)
+	}
+
+	return b.String()
+}
+
+func (l metricLabels) format(withStatus bool, le string) string {
+	pairs := []string{
+		`service="` + escapeLabel(l.service) + `"`,
+		`operation="` + escapeLabel(l.operation) + `"`,
+	}
+	if withStatus {
+		pairs = append(pairs, `status_class="`+escapeLabel(l.statusClass)+`"`)
+	}
+	if le != "" {
+		pairs = append(pairs, `le="`+le+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func sortedLabels[V any](values map[metricLabels]V) []metricLabels {
+	labels := make([]metricLabels, 0, len(values))
+	for l := range values {
+		labels = append(labels, l)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		a, b := labels[i], labels[j]
+		if a.service != b.service {
+			return a.service < b.service
+		}
+		if a.operation != b.operation {
+			return a.operation < b.operation
+		}
+		return a.statusClass < b.statusClass
+	})
+	return labels
+}
+
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,117 @@
+package unsend_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+type capturedMetrics struct {
+	mu       sync.Mutex
+	observed []unsend.RequestMetrics
+}
+
+func (m *capturedMetrics) ObserveRequest(ctx context.Context, metrics unsend.RequestMetrics) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.observed = append(m.observed, metrics)
+}
+
+func TestMetrics(t *testing.T) {
+	var emailCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/api/v1/emails/") && emailCalls.Add(1) == 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case strings.HasPrefix(r.URL.Path, "/api/v1/emails/"):
+			w.Write([]byte(`{"id": "email123"}`))
+		case strings.HasPrefix(r.URL.Path, "/api/v1/contactBooks/"):
+			w.Write([]byte(`{"contactId": "contact123"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	captured := &capturedMetrics{}
+	client, err := unsend.NewClient(
+		unsend.WithApiKey("test-api-key"),
+		unsend.WithBaseUrl(server.URL),
+		unsend.WithConfigFile(filepath.Join(t.TempDir(), "missing.toml")),
+		unsend.WithRetryPolicy(&unsend.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}),
+		unsend.WithMetrics(captured),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	ctx := context.Background()
+
+	client.Emails.GetEmail(ctx, unsend.GetEmailRequest{EmailId: "email123"})
+	client.Contacts.UpdateContact(ctx, unsend.UpdateContactRequest{ContactBookId: "book123", ContactId: "contact123", FirstName: "Ada"})
+	client.Domains.GetDomain(ctx, unsend.GetDomainRequest{DomainId: 9})
+
+	expected := []unsend.RequestMetrics{
+		{Service: "emails", Operation: "GetEmail", StatusClass: "2xx", StatusCode: 200, Retries: 1},
+		{Service: "contacts", Operation: "UpdateContact", StatusClass: "2xx", StatusCode: 200},
+		{Service: "domains", Operation: "GetDomain", StatusClass: "4xx", StatusCode: 404},
+	}
+	if len(captured.observed) != len(expected) {
+		t.Fatalf("expected %d observations, got %+v", len(expected), captured.observed)
+	}
+	for i, want := range expected {
+		got := captured.observed[i]
+		if got.Duration <= 0 {
+			t.Errorf("expected a duration for %s, got %v", got.Operation, got.Duration)
+		}
+		got.Duration = 0
+		if got != want {
+			t.Errorf("expected %+v, got %+v", want, got)
+		}
+	}
+}
+
+func TestPrometheusMetrics(t *testing.T) {
+	metrics := unsend.NewPrometheusMetrics(0.1, 1)
+	ctx := context.Background()
+
+	metrics.ObserveRequest(ctx, unsend.RequestMetrics{Service: "emails", Operation: "SendEmail", StatusClass: "2xx", Duration: 50 * time.Millisecond, Retries: 2})
+	metrics.ObserveRequest(ctx, unsend.RequestMetrics{Service: "emails", Operation: "SendEmail", StatusClass: "5xx", Duration: 500 * time.Millisecond})
+	metrics.ObserveRequest(ctx, unsend.RequestMetrics{Service: "domains", Operation: "GetDomains", StatusClass: unsend.STATUS_CLASS_ERROR, Duration: 2 * time.Second})
+
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(recorder.Body)
+
+	expected := []string{
+		"# TYPE unsend_requests_total counter",
+		`unsend_requests_total{service="domains",operation="GetDomains",status_class="error"} 1`,
+		`unsend_requests_total{service="emails",operation="SendEmail",status_class="2xx"} 1`,
+		`unsend_requests_total{service="emails",operation="SendEmail",status_class="5xx"} 1`,
+		`unsend_request_retries_total{service="emails",operation="SendEmail"} 2`,
+		"# TYPE unsend_request_duration_seconds histogram",
+		`unsend_request_duration_seconds_bucket{service="emails",operation="SendEmail",le="0.1"} 1`,
+		`unsend_request_duration_seconds_bucket{service="emails",operation="SendEmail",le="1"} 2`,
+		`unsend_request_duration_seconds_bucket{service="domains",operation="GetDomains",le="1"} 0`,
+		`unsend_request_duration_seconds_bucket{service="domains",operation="GetDomains",le="+Inf"} 1`,
+		`unsend_request_duration_seconds_sum{service="emails",operation="SendEmail"} 0.55`,
+		`unsend_request_duration_seconds_count{service="emails",operation="SendEmail"} 2`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(string(body), line+"\n") {
+			t.Errorf("expected output to contain %q, got\n%s", line, body)
+		}
+	}
+	if !strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Errorf("expected Prometheus content type, got %q", recorder.Header().Get("Content-Type"))
+	}
+}
@@ -0,0 +1,239 @@
+package unsend
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"unicode/utf8"
+)
+
+const HEADER_TEMPLATE_ID = "X-Unsend-Template-Id"
+
+// MAX_MULTIPART_DEPTH is how deeply ParseMessage follows multipart parts
+// inside multipart parts. Real mail rarely goes past 3.
+const MAX_MULTIPART_DEPTH = 10
+
+// MessageCharsetReader, when set, decodes charsets that ParseMessage doesn't
+// handle itself. UTF-8, US-ASCII, ISO-8859-1 and Windows-1252 are built in;
+// golang.org/x/net/html/charset.NewReaderLabel covers the rest.
+var MessageCharsetReader func(charset string, input io.Reader) (io.Reader, error)
+
+// ParseMessage converts an RFC 5322 message, such as one received over SMTP or
+// an archived .eml file, into a SendEmailRequest. Text and HTML bodies are
+// taken from the first text/plain and text/html parts and converted to UTF-8;
+// other parts, including inline images, become attachments.
+func ParseMessage(r io.Reader) (*SendEmailRequest, error) {
+	message, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse message: %w", err)
+	}
+
+	header := message.Header
+	request := &SendEmailRequest{}
+
+	if from, err := parseAddressList(header.Get("From")); err == nil && len(from) > 0 {
+		request.From = from[0].Address
+		if from[0].Name != "" {
+			request.From = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(from[0].Name) + `" <` + from[0].Address + `>`
+		}
+	}
+	for key, target := range map[string]*[]string{
+		"To":       &request.To,
+		"Cc":       &request.Cc,
+		"Bcc":      &request.Bcc,
+		"Reply-To": &request.ReplyTo,
+	} {
+		addresses, err := addressList(header, key)
+		if err != nil {
+			return nil, err
+		}
+		*target = addresses
+	}
+
+	request.Subject = decodeHeader(header.Get("Subject"))
+	request.TemplateId = header.Get(HEADER_TEMPLATE_ID)
+
+	if err := parsePart(request, header.Get("Content-Type"), header.Get("Content-Transfer-Encoding"), header.Get("Content-Disposition"), message.Body, 0); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+func addressList(header mail.Header, key string) ([]string, error) {
+	if header.Get(key) == "" {
+		return nil, nil
+	}
+
+	list, err := parseAddressList(header.Get(key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s header: %w", key, err)
+	}
+
+	addresses := make([]string, 0, len(list))
+	for _, address := range list {
+		addresses = append(addresses, address.Address)
+	}
+	return addresses, nil
+}
+
+func parseAddressList(value string) ([]*mail.Address, error) {
+	parser := &mail.AddressParser{WordDecoder: &mime.WordDecoder{CharsetReader: charsetReader}}
+	return parser.ParseList(value)
+}
+
+func decodeHeader(value string) string {
+	decoder := &mime.WordDecoder{CharsetReader: charsetReader}
+	decoded, err := decoder.DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
+
+func parsePart(request *SendEmailRequest, contentType, encoding, disposition string, body io.Reader, depth int) error {
+	if contentType == "" {
+		contentType = "text/plain"
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType, params = "application/octet-stream", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		if depth >= MAX_MULTIPART_DEPTH {
+			return fmt.Errorf("failed to read %s part: nested more than %d multipart levels deep", mediaType, MAX_MULTIPART_DEPTH)
+		}
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read %s part: %w", mediaType, err)
+			}
+
+			err = parsePart(request, part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part.Header.Get("Content-Disposition"), part, depth+1)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	content, err := io.ReadAll(decodeTransferEncoding(encoding, body))
+	if err != nil {
+		return fmt.Errorf("failed to decode %s part: %w", mediaType, err)
+	}
+
+	dispositionType, dispositionParams, _ := mime.ParseMediaType(disposition)
+	filename := decodeHeader(dispositionParams["filename"])
+	if filename == "" {
+		filename = decodeHeader(params["name"])
+	}
+
+	isBody := dispositionType != "attachment" && filename == ""
+	switch {
+	case isBody && mediaType == "text/plain" && request.Text == "":
+		request.Text, err = decodeCharset(params["charset"], content)
+	case isBody && mediaType == "text/html" && request.Html == "":
+		request.Html, err = decodeCharset(params["charset"], content)
+	default:
+		if filename == "" {
+			filename = fmt.Sprintf("attachment-%d%s", len(request.Attachments)+1, extensionForType(mediaType))
+		}
+		request.Attachments = append(request.Attachments, Attachments{
+			Filename: filename,
+			Content:  base64.StdEncoding.EncodeToString(content),
+		})
+	}
+
+	return err
+}
+
+func extensionForType(mediaType string) string {
+	if mediaType == "message/rfc822" {
+		return ".eml"
+	}
+	if extensions, _ := mime.ExtensionsByType(mediaType); len(extensions) > 0 {
+		return extensions[0]
+	}
+	return ""
+}
+
+// decodeCharset converts a text body to UTF-8 with \n line endings. Bodies in an unknown charset
+// are kept as they are if they happen to be valid UTF-8.
+func decodeCharset(charset string, content []byte) (string, error) {
+	reader, err := charsetReader(charset, strings.NewReader(string(content)))
+	if err != nil {
+		if utf8.Valid(content) {
+			return strings.ReplaceAll(string(content), "\r\n", "\n"), nil
+		}
+		return "", err
+	}
+
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode %s text: %w", charset, err)
+	}
+	return strings.ReplaceAll(string(decoded), "\r\n", "\n"), nil
+}
+
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(strings.Trim(charset, ` "`)) {
+	case "", "utf-8", "utf8", "us-ascii", "ascii":
+		return input, nil
+	case "iso-8859-1", "iso8859-1", "latin1", "latin-1", "l1":
+		return singleByteReader(input, nil)
+	case "windows-1252", "cp1252":
+		return singleByteReader(input, &windows1252)
+	}
+
+	if MessageCharsetReader != nil {
+		return MessageCharsetReader(charset, input)
+	}
+	return nil, fmt.Errorf("unsupported charset '%s'", charset)
+}
+
+// windows1252 maps 0x80-0x9F, the only range where Windows-1252 differs from
+// ISO-8859-1. Unassigned bytes keep their ISO-8859-1 control code.
+var windows1252 = [32]rune{
+	'\u20ac', '\u0081', '\u201a', '\u0192', '\u201e', '\u2026', '\u2020', '\u2021',
+	'\u02c6', '\u2030', '\u0160', '\u2039', '\u0152', '\u008d', '\u017d', '\u008f',
+	'\u0090', '\u2018', '\u2019', '\u201c', '\u201d', '\u2022', '\u2013', '\u2014',
+	'\u02dc', '\u2122', '\u0161', '\u203a', '\u0153', '\u009d', '\u017e', '\u0178',
+}
+
+func singleByteReader(input io.Reader, high *[32]rune) (io.Reader, error) {
+	content, err := io.ReadAll(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded strings.Builder
+	decoded.Grow(len(content))
+	for _, b := range content {
+		if high != nil && b >= 0x80 && b < 0xa0 {
+			decoded.WriteRune(high[b-0x80])
+		} else {
+			decoded.WriteRune(rune(b))
+		}
+	}
+	return strings.NewReader(decoded.String()), nil
+}
+
+func decodeTransferEncoding(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
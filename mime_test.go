@@ -0,0 +1,144 @@
+package unsend_test
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+func TestParseMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		expected unsend.SendEmailRequest
+	}{
+		{
+			name: "Plain text",
+			message: "From: hello@unsend.dev\r\n" +
+				"To: a@b.c, \"Bee\" <b@b.c>\r\n" +
+				"Subject: Hi\r\n" +
+				"\r\n" +
+				"Line one\r\nLine two\r\n",
+			expected: unsend.SendEmailRequest{
+				From:    "hello@unsend.dev",
+				To:      []string{"a@b.c", "b@b.c"},
+				Subject: "Hi",
+				Text:    "Line one\nLine two\n",
+			},
+		},
+		{
+			name: "Encoded headers and charsets",
+			message: "From: =?ISO-8859-1?Q?Andr=E9?= <andre@unsend.dev>\r\n" +
+				"To: a@b.c\r\n" +
+				"Subject: =?windows-1252?Q?=93Quoted=94?= =?UTF-8?B?4pyT?=\r\n" +
+				"Content-Type: multipart/alternative; boundary=alt\r\n" +
+				"\r\n" +
+				"--alt\r\n" +
+				"Content-Type: text/plain; charset=iso-8859-1\r\n" +
+				"Content-Transfer-Encoding: quoted-printable\r\n" +
+				"\r\n" +
+				"Caf=E9\r\n" +
+				"--alt\r\n" +
+				"Content-Type: text/html; charset=\"windows-1252\"\r\n" +
+				"Content-Transfer-Encoding: 8bit\r\n" +
+				"\r\n" +
+				"<p>\x80 5</p>\r\n" +
+				"--alt--\r\n",
+			expected: unsend.SendEmailRequest{
+				From:    `"André" <andre@unsend.dev>`,
+				To:      []string{"a@b.c"},
+				Subject: "“Quoted”✓",
+				Text:    "Café",
+				Html:    "<p>€ 5</p>",
+			},
+		},
+		{
+			name: "Nested multipart with attachments",
+			message: "From: hello@unsend.dev\r\n" +
+				"To: a@b.c\r\n" +
+				"X-Unsend-Template-Id: welcome\r\n" +
+				"Content-Type: multipart/mixed; boundary=mixed\r\n" +
+				"\r\n" +
+				"--mixed\r\n" +
+				"Content-Type: multipart/related; boundary=related\r\n" +
+				"\r\n" +
+				"--related\r\n" +
+				"Content-Type: text/html\r\n" +
+				"\r\n" +
+				"<img src=\"cid:logo\">\r\n" +
+				"--related\r\n" +
+				"Content-Type: image/png; name=logo.png\r\n" +
+				"Content-ID: <logo>\r\n" +
+				"Content-Transfer-Encoding: base64\r\n" +
+				"\r\n" +
+				"iVBO\r\nRw==\r\n" +
+				"--related--\r\n" +
+				"--mixed\r\n" +
+				"Content-Type: text/plain\r\n" +
+				"Content-Disposition: attachment; filename*=UTF-8''r%C3%A9sum%C3%A9.txt\r\n" +
+				"\r\n" +
+				"cv\r\n" +
+				"--mixed--\r\n",
+			expected: unsend.SendEmailRequest{
+				From:       "hello@unsend.dev",
+				To:         []string{"a@b.c"},
+				TemplateId: "welcome",
+				Html:       `<img src="cid:logo">`,
+				Attachments: []unsend.Attachments{
+					{Filename: "logo.png", Content: "iVBORw=="},
+					{Filename: "résumé.txt", Content: base64.StdEncoding.EncodeToString([]byte("cv"))},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := unsend.ParseMessage(strings.NewReader(tt.message))
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(*request, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, *request)
+			}
+		})
+	}
+}
+
+func TestParseMessageUnsupportedCharset(t *testing.T) {
+	message := "From: hello@unsend.dev\r\n" +
+		"Content-Type: text/plain; charset=koi8-r\r\n" +
+		"\r\n" +
+		"\xf0\xd2\xc9\xd7\xc5\xd4\r\n"
+
+	if _, err := unsend.ParseMessage(strings.NewReader(message)); err == nil {
+		t.Errorf("expected error for a charset that can't be decoded")
+	}
+}
+
+func TestParseMessageNestingLimit(t *testing.T) {
+	nested := func(depth int) string {
+		message := "From: hello@unsend.dev\r\n"
+		for i := 0; i < depth; i++ {
+			message += fmt.Sprintf("Content-Type: multipart/mixed; boundary=b%d\r\n\r\n--b%d\r\n", i, i)
+		}
+		message += "Content-Type: text/plain\r\n\r\nHello"
+		for i := depth - 1; i >= 0; i-- {
+			message += fmt.Sprintf("\r\n--b%d--\r\n", i)
+		}
+		return message
+	}
+
+	request, err := unsend.ParseMessage(strings.NewReader(nested(unsend.MAX_MULTIPART_DEPTH)))
+	if err != nil || request.Text != "Hello" {
+		t.Errorf("expected %d levels to be parsed, got %+v, %v", unsend.MAX_MULTIPART_DEPTH, request, err)
+	}
+
+	if _, err := unsend.ParseMessage(strings.NewReader(nested(unsend.MAX_MULTIPART_DEPTH + 1))); err == nil {
+		t.Errorf("expected error for a message nested deeper than %d levels", unsend.MAX_MULTIPART_DEPTH)
+	}
+}
@@ -0,0 +1,428 @@
+// Code generated by openapigen from openapi/unsend.json. DO NOT EDIT.
+
+package unsend
+
+import (
+	"encoding/json"
+)
+
+type GetEmailRequest struct {
+	EmailId string
+}
+
+type SendEmailRequest struct {
+	To          []string               `json:"to"`
+	From        string                 `json:"from"`
+	Subject     string                 `json:"subject,omitempty"`
+	TemplateId  string                 `json:"templateId,omitempty"`
+	Variables   map[string]interface{} `json:"variables,omitempty"`
+	ReplyTo     []string               `json:"replyTo,omitempty"`
+	Cc          []string               `json:"cc,omitempty"`
+	Bcc         []string               `json:"bcc,omitempty"`
+	Text        string                 `json:"text,omitempty"`
+	Html        string                 `json:"html,omitempty"`
+	Attachments []Attachments          `json:"attachments,omitempty"`
+	ScheduledAt string                 `json:"scheduledAt,omitempty"`
+
+	// Deprecated: use ScheduledAt. SendEmail sends ScheduleAt as scheduledAt when ScheduledAt is empty.
+	ScheduleAt string `json:"scheduleAt,omitempty"`
+}
+
+type UpdateScheduleRequest struct {
+	EmailId     string `json:"-"`
+	ScheduledAt string `json:"scheduledAt"`
+}
+
+type CancelScheduleRequest struct {
+	EmailId string
+}
+
+type GetContactRequest struct {
+	ContactBookId string
+	ContactId     string
+}
+
+type CreateContactRequest struct {
+	ContactBookId string                 `json:"-"`
+	Email         string                 `json:"email"`
+	FirstName     string                 `json:"firstName,omitempty"`
+	LastName      string                 `json:"lastName,omitempty"`
+	Properties    map[string]interface{} `json:"properties,omitempty"`
+	Subscribed    bool                   `json:"subscribed"`
+}
+
+type UpsertContactRequest struct {
+	ContactBookId string                 `json:"-"`
+	ContactId     string                 `json:"-"`
+	Email         string                 `json:"email"`
+	FirstName     string                 `json:"firstName,omitempty"`
+	LastName      string                 `json:"lastName,omitempty"`
+	Properties    map[string]interface{} `json:"properties,omitempty"`
+	Subscribed    bool                   `json:"subscribed"`
+}
+
+type UpdateContactRequest struct {
+	ContactBookId string                 `json:"-"`
+	ContactId     string                 `json:"-"`
+	FirstName     string                 `json:"firstName,omitempty"`
+	LastName      string                 `json:"lastName,omitempty"`
+	Properties    map[string]interface{} `json:"properties,omitempty"`
+	Subscribed    bool                   `json:"subscribed"`
+}
+
+type DeleteContactRequest struct {
+	ContactBookId string
+	ContactId     string
+}
+
+type GetDomainRequest struct {
+	DomainId int
+}
+
+type CreateDomainRequest struct {
+	Name   string `json:"name"`
+	Region string `json:"region"`
+}
+
+type VerifyDomainRequest struct {
+	DomainId int
+}
+
+type DeleteDomainRequest struct {
+	DomainId int
+}
+
+type CreateCampaignRequest struct {
+	Name          string   `json:"name"`
+	From          string   `json:"from"`
+	Subject       string   `json:"subject"`
+	ContactBookId string   `json:"contactBookId"`
+	PreviewText   string   `json:"previewText,omitempty"`
+	Html          string   `json:"html,omitempty"`
+	ReplyTo       []string `json:"replyTo,omitempty"`
+	Cc            []string `json:"cc,omitempty"`
+	Bcc           []string `json:"bcc,omitempty"`
+	SendNow       bool     `json:"sendNow,omitempty"`
+	ScheduledAt   string   `json:"scheduledAt,omitempty"`
+	BatchSize     int      `json:"batchSize,omitempty"`
+}
+
+type GetCampaignRequest struct {
+	CampaignId string
+}
+
+type ListCampaignsRequest struct {
+	Page   int
+	Status CampaignStatus
+}
+
+type UpdateCampaignRequest struct {
+	CampaignId    string   `json:"-"`
+	Name          string   `json:"name,omitempty"`
+	From          string   `json:"from,omitempty"`
+	Subject       string   `json:"subject,omitempty"`
+	ContactBookId string   `json:"contactBookId,omitempty"`
+	PreviewText   string   `json:"previewText,omitempty"`
+	Html          string   `json:"html,omitempty"`
+	ReplyTo       []string `json:"replyTo,omitempty"`
+	Cc            []string `json:"cc,omitempty"`
+	Bcc           []string `json:"bcc,omitempty"`
+}
+
+type ScheduleCampaignRequest struct {
+	CampaignId  string `json:"-"`
+	ScheduledAt string `json:"scheduledAt"`
+	BatchSize   int    `json:"batchSize,omitempty"`
+}
+
+type SendCampaignRequest struct {
+	CampaignId string
+}
+
+type PauseCampaignRequest struct {
+	CampaignId string
+}
+
+type ResumeCampaignRequest struct {
+	CampaignId string
+}
+
+type DeleteCampaignRequest struct {
+	CampaignId string
+}
+
+type EmailEvents struct {
+	EmailId   string                     `json:"emailId"`
+	Status    string                     `json:"status"`
+	CreatedAt string                     `json:"createdAt"`
+	Data      interface{}                `json:"data"`
+	Extra     map[string]json.RawMessage `json:"-"`
+}
+
+type Attachments struct {
+	Filename string `json:"filename"`
+	Content  string `json:"content"`
+}
+
+type GetEmailResponse struct {
+	Id          string                     `json:"id"`
+	TeamId      int                        `json:"teamId"`
+	To          []string                   `json:"to"`
+	From        string                     `json:"from"`
+	Subject     string                     `json:"subject"`
+	Html        string                     `json:"html"`
+	Text        string                     `json:"text"`
+	CreatedAt   string                     `json:"createdAt"`
+	UpdatedAt   string                     `json:"updatedAt"`
+	EmailEvents []EmailEvents              `json:"emailEvents"`
+	ReplyTo     []string                   `json:"replyTo"`
+	Cc          []string                   `json:"cc"`
+	Bcc         []string                   `json:"bcc"`
+	Extra       map[string]json.RawMessage `json:"-"`
+}
+
+type EmailIdResponse struct {
+	EmailId string                     `json:"emailId"`
+	Extra   map[string]json.RawMessage `json:"-"`
+}
+
+type GetContactResponse struct {
+	Id            string                     `json:"id"`
+	FirstName     string                     `json:"firstName"`
+	LastName      string                     `json:"lastName"`
+	Email         string                     `json:"email"`
+	Subscribed    bool                       `json:"subscribed"`
+	Properties    map[string]interface{}     `json:"properties"`
+	ContactBookID string                     `json:"contactBookId"`
+	CreatedAt     string                     `json:"createdAt"`
+	UpdatedAt     string                     `json:"updatedAt"`
+	Extra         map[string]json.RawMessage `json:"-"`
+}
+
+type ContactIdResponse struct {
+	ContactId string                     `json:"contactId"`
+	Extra     map[string]json.RawMessage `json:"-"`
+}
+
+type DeleteContactResponse struct {
+	Success bool                       `json:"success"`
+	Extra   map[string]json.RawMessage `json:"-"`
+}
+
+type GetDomainsResponse struct {
+	Id            int                        `json:"id"`
+	Name          string                     `json:"name"`
+	TeamId        int                        `json:"teamId"`
+	Status        string                     `json:"status"`
+	PublicKey     string                     `json:"publicKey"`
+	CreatedAt     string                     `json:"createdAt"`
+	UpdatedAt     string                     `json:"updatedAt"`
+	Region        string                     `json:"region"`
+	ClickTracking bool                       `json:"clickTracking"`
+	OpenTracking  bool                       `json:"openTracking"`
+	DkimStatus    string                     `json:"dkimStatus,omitempty"`
+	SpfDetails    string                     `json:"spfDetails,omitempty"`
+	Subdomain     string                     `json:"subdomain,omitempty"`
+	DkimSelector  string                     `json:"dkimSelector,omitempty"`
+	DnsRecords    []DnsRecord                `json:"dnsRecords,omitempty"`
+	Extra         map[string]json.RawMessage `json:"-"`
+}
+
+type DnsRecord struct {
+	Type     string                     `json:"type"`
+	Name     string                     `json:"name"`
+	Value    string                     `json:"value"`
+	Ttl      string                     `json:"ttl,omitempty"`
+	Priority string                     `json:"priority,omitempty"`
+	Status   string                     `json:"status,omitempty"`
+	Extra    map[string]json.RawMessage `json:"-"`
+}
+
+type VerifyDomainResponse struct {
+	Message string                     `json:"message"`
+	Extra   map[string]json.RawMessage `json:"-"`
+}
+
+type CampaignStatus string
+
+const (
+	CAMPAIGN_STATUS_DRAFT     CampaignStatus = "DRAFT"
+	CAMPAIGN_STATUS_SCHEDULED CampaignStatus = "SCHEDULED"
+	CAMPAIGN_STATUS_RUNNING   CampaignStatus = "RUNNING"
+	CAMPAIGN_STATUS_PAUSED    CampaignStatus = "PAUSED"
+	CAMPAIGN_STATUS_SENT      CampaignStatus = "SENT"
+)
+
+type CampaignStats struct {
+	Total        int                        `json:"total"`
+	Sent         int                        `json:"sent"`
+	Delivered    int                        `json:"delivered"`
+	Opened       int                        `json:"opened"`
+	Clicked      int                        `json:"clicked"`
+	Bounced      int                        `json:"bounced"`
+	Complained   int                        `json:"complained"`
+	Unsubscribed int                        `json:"unsubscribed"`
+	Extra        map[string]json.RawMessage `json:"-"`
+}
+
+type GetCampaignResponse struct {
+	Id            string                     `json:"id"`
+	Name          string                     `json:"name"`
+	TeamId        int                        `json:"teamId"`
+	From          string                     `json:"from"`
+	Subject       string                     `json:"subject"`
+	PreviewText   string                     `json:"previewText,omitempty"`
+	ContactBookId string                     `json:"contactBookId"`
+	Html          string                     `json:"html,omitempty"`
+	ReplyTo       []string                   `json:"replyTo,omitempty"`
+	Cc            []string                   `json:"cc,omitempty"`
+	Bcc           []string                   `json:"bcc,omitempty"`
+	Status        CampaignStatus             `json:"status"`
+	ScheduledAt   string                     `json:"scheduledAt,omitempty"`
+	BatchSize     int                        `json:"batchSize,omitempty"`
+	Stats         CampaignStats              `json:"stats"`
+	CreatedAt     string                     `json:"createdAt"`
+	UpdatedAt     string                     `json:"updatedAt"`
+	Extra         map[string]json.RawMessage `json:"-"`
+}
+
+type ListCampaignsResponse struct {
+	Campaigns []GetCampaignResponse      `json:"campaigns"`
+	TotalPage int                        `json:"totalPage"`
+	Extra     map[string]json.RawMessage `json:"-"`
+}
+
+type CampaignActionResponse struct {
+	Success bool                       `json:"success"`
+	Extra   map[string]json.RawMessage `json:"-"`
+}
+
+func (r *EmailEvents) UnmarshalJSON(data []byte) error {
+	type plain EmailEvents
+	return unmarshalWithExtra(data, (*plain)(r), &r.Extra)
+}
+
+func (r EmailEvents) MarshalJSON() ([]byte, error) {
+	type plain EmailEvents
+	return marshalWithExtra(plain(r), r.Extra)
+}
+
+func (r *GetEmailResponse) UnmarshalJSON(data []byte) error {
+	type plain GetEmailResponse
+	return unmarshalWithExtra(data, (*plain)(r), &r.Extra)
+}
+
+func (r GetEmailResponse) MarshalJSON() ([]byte, error) {
+	type plain GetEmailResponse
+	return marshalWithExtra(plain(r), r.Extra)
+}
+
+func (r *EmailIdResponse) UnmarshalJSON(data []byte) error {
+	type plain EmailIdResponse
+	return unmarshalWithExtra(data, (*plain)(r), &r.Extra)
+}
+
+func (r EmailIdResponse) MarshalJSON() ([]byte, error) {
+	type plain EmailIdResponse
+	return marshalWithExtra(plain(r), r.Extra)
+}
+
+func (r *GetContactResponse) UnmarshalJSON(data []byte) error {
+	type plain GetContactResponse
+	return unmarshalWithExtra(data, (*plain)(r), &r.Extra)
+}
+
+func (r GetContactResponse) MarshalJSON() ([]byte, error) {
+	type plain GetContactResponse
+	return marshalWithExtra(plain(r), r.Extra)
+}
+
+func (r *ContactIdResponse) UnmarshalJSON(data []byte) error {
+	type plain ContactIdResponse
+	return unmarshalWithExtra(data, (*plain)(r), &r.Extra)
+}
+
+func (r ContactIdResponse) MarshalJSON() ([]byte, error) {
+	type plain ContactIdResponse
+	return marshalWithExtra(plain(r), r.Extra)
+}
+
+func (r *DeleteContactResponse) UnmarshalJSON(data []byte) error {
+	type plain DeleteContactResponse
+	return unmarshalWithExtra(data, (*plain)(r), &r.Extra)
+}
+
+func (r DeleteContactResponse) MarshalJSON() ([]byte, error) {
+	type plain DeleteContactResponse
+	return marshalWithExtra(plain(r), r.Extra)
+}
+
+func (r *GetDomainsResponse) UnmarshalJSON(data []byte) error {
+	type plain GetDomainsResponse
+	return unmarshalWithExtra(data, (*plain)(r), &r.Extra)
+}
+
+func (r GetDomainsResponse) MarshalJSON() ([]byte, error) {
+	type plain GetDomainsResponse
+	return marshalWithExtra(plain(r), r.Extra)
+}
+
+func (r *DnsRecord) UnmarshalJSON(data []byte) error {
+	type plain DnsRecord
+	return unmarshalWithExtra(data, (*plain)(r), &r.Extra)
+}
+
+func (r DnsRecord) MarshalJSON() ([]byte, error) {
+	type plain DnsRecord
+	return marshalWithExtra(plain(r), r.Extra)
+}
+
+func (r *VerifyDomainResponse) UnmarshalJSON(data []byte) error {
+	type plain VerifyDomainResponse
+	return unmarshalWithExtra(data, (*plain)(r), &r.Extra)
+}
+
+func (r VerifyDomainResponse) MarshalJSON() ([]byte, error) {
+	type plain VerifyDomainResponse
+	return marshalWithExtra(plain(r), r.Extra)
+}
+
+func (r *CampaignStats) UnmarshalJSON(data []byte) error {
+	type plain CampaignStats
+	return unmarshalWithExtra(data, (*plain)(r), &r.Extra)
+}
+
+func (r CampaignStats) MarshalJSON() ([]byte, error) {
+	type plain CampaignStats
+	return marshalWithExtra(plain(r), r.Extra)
+}
+
+func (r *GetCampaignResponse) UnmarshalJSON(data []byte) error {
+	type plain GetCampaignResponse
+	return unmarshalWithExtra(data, (*plain)(r), &r.Extra)
+}
+
+func (r GetCampaignResponse) MarshalJSON() ([]byte, error) {
+	type plain GetCampaignResponse
+	return marshalWithExtra(plain(r), r.Extra)
+}
+
+func (r *ListCampaignsResponse) UnmarshalJSON(data []byte) error {
+	type plain ListCampaignsResponse
+	return unmarshalWithExtra(data, (*plain)(r), &r.Extra)
+}
+
+func (r ListCampaignsResponse) MarshalJSON() ([]byte, error) {
+	type plain ListCampaignsResponse
+	return marshalWithExtra(plain(r), r.Extra)
+}
+
+func (r *CampaignActionResponse) UnmarshalJSON(data []byte) error {
+	type plain CampaignActionResponse
+	return unmarshalWithExtra(data, (*plain)(r), &r.Extra)
+}
+
+func (r CampaignActionResponse) MarshalJSON() ([]byte, error) {
+	type plain CampaignActionResponse
+	return marshalWithExtra(plain(r), r.Extra)
+}
@@ -0,0 +1,196 @@
+package unsend
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	apiKey                  string
+	baseUrl                 string
+	timeout                 time.Duration
+	profile                 string
+	configFile              string
+	credentials             CredentialsProvider
+	transport               http.RoundTripper
+	logger                  *slog.Logger
+	redactFields            []string
+	rateLimiter             *RateLimiter
+	retryPolicy             *RetryPolicy
+	metrics                 Metrics
+	tracer                  Tracer
+	capabilityDetection     bool
+	idempotency             *IdempotencyOptions
+	dryRun                  bool
+	allowedRecipientDomains []string
+	recipientOverride       *RecipientOverride
+	previewMode             bool
+	previewAddr             string
+	verifySender            bool
+	senderVerificationTTL   time.Duration
+}
+
+// WithApiKey overrides the UNSEND_API_KEY environment variable.
+func WithApiKey(apiKey string) ClientOption {
+	return func(o *clientOptions) {
+		o.apiKey = apiKey
+	}
+}
+
+// WithBaseUrl overrides the UNSEND_BASE_URL environment variable.
+func WithBaseUrl(baseUrl string) ClientOption {
+	return func(o *clientOptions) {
+		o.baseUrl = baseUrl
+	}
+}
+
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithProfile selects a named profile from the config file, taking
+// precedence over UNSEND_PROFILE.
+func WithProfile(name string) ClientOption {
+	return func(o *clientOptions) {
+		o.profile = name
+	}
+}
+
+// WithConfigFile reads profiles from path instead of DefaultConfigPath.
+func WithConfigFile(path string) ClientOption {
+	return func(o *clientOptions) {
+		o.configFile = path
+	}
+}
+
+// WithCredentials makes every request ask provider for the API key. When set,
+// NewClient no longer requires an API key from options, env or profile.
+func WithCredentials(provider CredentialsProvider) ClientOption {
+	return func(o *clientOptions) {
+		o.credentials = provider
+	}
+}
+
+// WithLogger logs each request to logger. Nothing is logged without it.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(o *clientOptions) {
+		o.logger = logger
+	}
+}
+
+// WithRedactedFields adds JSON keys to DEFAULT_REDACT_FIELDS when logging
+// bodies at debug level.
+func WithRedactedFields(fields ...string) ClientOption {
+	return func(o *clientOptions) {
+		o.redactFields = append(o.redactFields, fields...)
+	}
+}
+
+// WithRateLimiter makes every request wait for limiter before it is sent.
+func WithRateLimiter(limiter *RateLimiter) ClientOption {
+	return func(o *clientOptions) {
+		o.rateLimiter = limiter
+	}
+}
+
+// WithRetryPolicy retries failed requests according to policy. Requests are
+// sent once without it.
+func WithRetryPolicy(policy *RetryPolicy) ClientOption {
+	return func(o *clientOptions) {
+		o.retryPolicy = policy
+	}
+}
+
+// WithIdempotency enables the idempotency cache, and with AutoGenerate adds
+// keys to SendEmail and contact writes so they can be retried safely.
+func WithIdempotency(options IdempotencyOptions) ClientOption {
+	return func(o *clientOptions) {
+		o.idempotency = &options
+	}
+}
+
+// WithSenderVerification makes SendEmail check that the From domain is a
+// verified domain on the account before calling the API. The domain list is
+// cached for ttl.
+func WithSenderVerification(ttl time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.verifySender = true
+		o.senderVerificationTTL = ttl
+	}
+}
+
+// WithPreviewMode captures emails in a PreviewEmails instead of sending them,
+// and serves the preview UI on addr (default $UNSEND_PREVIEW_ADDR, then
+// 127.0.0.1:8025, or a free port when that is taken). Setting
+// UNSEND_MODE=preview does the same. No API key is needed in preview mode;
+// only Emails is replaced.
+func WithPreviewMode(addr string) ClientOption {
+	return func(o *clientOptions) {
+		o.previewMode = true
+		o.previewAddr = addr
+	}
+}
+
+// WithDryRun validates writes but answers them locally with synthetic ids
+// instead of calling the API. Reads still reach the API. Setting
+// UNSEND_MODE=dry-run does the same; see Client.Sandbox for what was recorded.
+func WithDryRun() ClientOption {
+	return func(o *clientOptions) {
+		o.dryRun = true
+	}
+}
+
+// WithRecipientAllowlist only sends email to recipients at the given domains.
+// Other recipients are dropped, and emails left with no To address are not
+// sent.
+func WithRecipientAllowlist(domains ...string) ClientOption {
+	return func(o *clientOptions) {
+		o.allowedRecipientDomains = append(o.allowedRecipientDomains, domains...)
+	}
+}
+
+// WithRecipientOverride sends every email to the override's addresses instead
+// of its real recipients. Setting UNSEND_RECIPIENT_OVERRIDE to a comma
+// separated list of addresses does the same with the default settings.
+func WithRecipientOverride(override RecipientOverride) ClientOption {
+	return func(o *clientOptions) {
+		o.recipientOverride = &override
+	}
+}
+
+// WithTransport sends requests through transport after the API key and SDK
+// headers are added, for proxies, test doubles or a vcr.Recorder.
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(o *clientOptions) {
+		o.transport = transport
+	}
+}
+
+// WithMetrics reports every API call to metrics, such as a
+// NewPrometheusMetrics.
+func WithMetrics(metrics Metrics) ClientOption {
+	return func(o *clientOptions) {
+		o.metrics = metrics
+	}
+}
+
+// WithTracer starts a span for every API call and propagates it to the API.
+func WithTracer(tracer Tracer) ClientOption {
+	return func(o *clientOptions) {
+		o.tracer = tracer
+	}
+}
+
+// WithCapabilityDetection probes the server's features on first use, so
+// operations it doesn't support fail with ErrUnsupportedByServer instead of a
+// 404. Useful with self-hosted servers running older versions.
+func WithCapabilityDetection() ClientOption {
+	return func(o *clientOptions) {
+		o.capabilityDetection = true
+	}
+}
@@ -0,0 +1,94 @@
+// Package otelunsend is an unsend.Tracer backed by OpenTelemetry, so SDK calls
+// are spans in the traces of the application using it: a SendEmail made while
+// handling a request traced by otelhttp is a child of the handler's span.
+//
+// It is a separate module so the SDK itself doesn't depend on OpenTelemetry.
+package otelunsend
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/QGeeDev/unsend-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const INSTRUMENTATION_NAME = "github.com/QGeeDev/unsend-go/otelunsend"
+
+// Tracer implements unsend.Tracer with an OpenTelemetry tracer, and propagates
+// the span to the API with an OpenTelemetry propagator.
+type Tracer struct {
+	Tracer     trace.Tracer
+	Propagator propagation.TextMapPropagator
+}
+
+// NewTracer returns a Tracer using provider and propagator, or the global ones
+// from otel when nil.
+func NewTracer(provider trace.TracerProvider, propagator propagation.TextMapPropagator) *Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+
+	return &Tracer{
+		Tracer:     provider.Tracer(INSTRUMENTATION_NAME),
+		Propagator: propagator,
+	}
+}
+
+func (t *Tracer) Start(ctx context.Context, name string, attributes ...unsend.Attribute) (context.Context, unsend.Span) {
+	ctx, s := t.Tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(convert(attributes)...),
+	)
+	return ctx, span{s}
+}
+
+func (t *Tracer) Inject(ctx context.Context, header http.Header) {
+	t.Propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+type span struct {
+	span trace.Span
+}
+
+func (s span) SetAttributes(attributes ...unsend.Attribute) {
+	s.span.SetAttributes(convert(attributes)...)
+}
+
+func (s span) RecordError(err error) {
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+func (s span) End() {
+	s.span.End()
+}
+
+func convert(attributes []unsend.Attribute) []attribute.KeyValue {
+	converted := make([]attribute.KeyValue, 0, len(attributes))
+	for _, a := range attributes {
+		switch value := a.Value.(type) {
+		case string:
+			converted = append(converted, attribute.String(a.Key, value))
+		case int:
+			converted = append(converted, attribute.Int(a.Key, value))
+		case int64:
+			converted = append(converted, attribute.Int64(a.Key, value))
+		case float64:
+			converted = append(converted, attribute.Float64(a.Key, value))
+		case bool:
+			converted = append(converted, attribute.Bool(a.Key, value))
+		default:
+			converted = append(converted, attribute.String(a.Key, fmt.Sprint(value)))
+		}
+	}
+	return converted
+}
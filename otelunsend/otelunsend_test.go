@@ -0,0 +1,92 @@
+package otelunsend_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/QGeeDev/unsend-go"
+	"github.com/QGeeDev/unsend-go/otelunsend"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSpansAreChildrenOfHandlerSpans(t *testing.T) {
+	var received string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get("traceparent")
+		if strings.HasSuffix(r.URL.Path, "/contacts/") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"emailId": "email123"}`))
+	}))
+	defer api.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	client, err := unsend.NewClient(
+		unsend.WithApiKey("test-api-key"),
+		unsend.WithBaseUrl(api.URL),
+		unsend.WithConfigFile(filepath.Join(t.TempDir(), "missing.toml")),
+		unsend.WithTracer(otelunsend.NewTracer(provider, propagation.TraceContext{})),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	ctx, handler := provider.Tracer("handler").Start(context.Background(), "POST /signup")
+	client.Emails.SendEmail(ctx, unsend.SendEmailRequest{To: []string{"a@b.c"}, From: "hello@unsend.dev", Text: "Hi"})
+	client.Contacts.CreateContact(ctx, unsend.CreateContactRequest{ContactBookId: "book123", Email: "a@b.c"})
+	handler.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+
+	send, create := spans[0], spans[1]
+	parent := handler.SpanContext()
+	for _, span := range []sdktrace.ReadOnlySpan{send, create} {
+		if span.Parent().SpanID() != parent.SpanID() || span.SpanContext().TraceID() != parent.TraceID() {
+			t.Errorf("expected %s to be a child of the handler span", span.Name())
+		}
+		if span.SpanKind() != trace.SpanKindClient {
+			t.Errorf("expected %s to be a client span, got %s", span.Name(), span.SpanKind())
+		}
+	}
+
+	if send.Name() != "unsend.SendEmail" {
+		t.Errorf("expected unsend.SendEmail, got %s", send.Name())
+	}
+	attributes := map[attribute.Key]attribute.Value{}
+	for _, kv := range send.Attributes() {
+		attributes[kv.Key] = kv.Value
+	}
+	expected := map[attribute.Key]attribute.Value{
+		unsend.ATTRIBUTE_OPERATION:        attribute.StringValue("SendEmail"),
+		unsend.ATTRIBUTE_HTTP_STATUS_CODE: attribute.IntValue(http.StatusOK),
+		unsend.ATTRIBUTE_RETRY_COUNT:      attribute.IntValue(0),
+		unsend.ATTRIBUTE_EMAIL_ID:         attribute.StringValue("email123"),
+	}
+	for key, value := range expected {
+		if attributes[key] != value {
+			t.Errorf("expected %s to be %v, got %v", key, value.Emit(), attributes[key].Emit())
+		}
+	}
+
+	if create.Status().Code != codes.Error {
+		t.Errorf("expected the failed CreateContact span to have an error status, got %v", create.Status())
+	}
+	if !strings.Contains(received, create.SpanContext().SpanID().String()) {
+		t.Errorf("expected the API to receive the SDK span in traceparent, got %q", received)
+	}
+}
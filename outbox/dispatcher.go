@@ -0,0 +1,254 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+const DEFAULT_POLL_INTERVAL = time.Second
+const DEFAULT_BATCH_SIZE = 10
+const DEFAULT_MAX_ATTEMPTS = 5
+const DEFAULT_INITIAL_BACKOFF = 5 * time.Second
+const DEFAULT_MAX_BACKOFF = 10 * time.Minute
+
+var ErrDispatcherStopped = errors.New("outbox dispatcher stopped")
+
+type DispatcherOptions struct {
+	// PollInterval is how often the store is checked for due messages.
+	PollInterval time.Duration
+	// BatchSize is how many messages are read from the store at a time.
+	BatchSize int
+	// MaxAttempts is how many failed sends move a message to the dead
+	// letters. Errors the API will never accept are dead-lettered at once.
+	MaxAttempts int
+	// InitialBackoff doubles after each failed attempt up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// OnDeadLetter is called after a message is dead-lettered.
+	OnDeadLetter func(Message)
+	Logger       *slog.Logger
+}
+
+// Dispatcher drains a Store through Emails.SendEmail, sending each message
+// with its Id as the idempotency key.
+type Dispatcher struct {
+	Store   Store
+	Emails  unsend.Emails
+	Options DispatcherOptions
+
+	mu      sync.Mutex
+	running bool
+	stop    chan struct{}
+	done    chan struct{}
+	cancel  context.CancelFunc
+	wake    chan struct{}
+	now     func() time.Time
+}
+
+func NewDispatcher(store Store, emails unsend.Emails, options DispatcherOptions) *Dispatcher {
+	if options.PollInterval <= 0 {
+		options.PollInterval = DEFAULT_POLL_INTERVAL
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = DEFAULT_BATCH_SIZE
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = DEFAULT_MAX_ATTEMPTS
+	}
+	if options.InitialBackoff <= 0 {
+		options.InitialBackoff = DEFAULT_INITIAL_BACKOFF
+	}
+	if options.MaxBackoff <= 0 {
+		options.MaxBackoff = DEFAULT_MAX_BACKOFF
+	}
+
+	return &Dispatcher{
+		Store:   store,
+		Emails:  emails,
+		Options: options,
+		wake:    make(chan struct{}, 1),
+		now:     time.Now,
+	}
+}
+
+// Enqueue stores a new message for request and wakes the dispatcher.
+func (d *Dispatcher) Enqueue(ctx context.Context, request unsend.SendEmailRequest) (Message, error) {
+	message, err := NewMessage(request)
+	if err != nil {
+		return Message{}, err
+	}
+	if err := d.Store.Enqueue(ctx, message); err != nil {
+		return Message{}, err
+	}
+
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+	return message, nil
+}
+
+// Run sends due messages until ctx is done or Shutdown is called. It returns
+// nil after Shutdown and ctx.Err() otherwise.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	d.mu.Lock()
+	if d.running {
+		d.mu.Unlock()
+		return errors.New("[ERROR]: outbox dispatcher is already running")
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	d.running = true
+	d.stop = make(chan struct{})
+	d.done = make(chan struct{})
+	d.cancel = cancel
+	stop, done := d.stop, d.done
+	d.mu.Unlock()
+
+	defer func() {
+		cancel()
+		d.mu.Lock()
+		d.running = false
+		d.mu.Unlock()
+		close(done)
+	}()
+
+	ticker := time.NewTicker(d.Options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			sent, err := d.dispatch(runCtx, stop)
+			if err != nil && !errors.Is(err, ErrDispatcherStopped) {
+				d.log(runCtx, slog.LevelWarn, "outbox dispatch failed", "error", err)
+			}
+			if sent < d.Options.BatchSize || err != nil {
+				break
+			}
+		}
+
+		select {
+		case <-stop:
+			return nil
+		case <-runCtx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// Shutdown stops Run from taking new messages and waits for the message being
+// sent to finish. If ctx is done first the send is cancelled; the message
+// stays pending and is sent again on the next Run.
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.running {
+		d.mu.Unlock()
+		return nil
+	}
+	stop, done, cancel := d.stop, d.done, d.cancel
+	select {
+	case <-stop:
+	default:
+		close(stop)
+	}
+	d.mu.Unlock()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// DispatchOnce sends one batch of due messages and returns how many were
+// taken from the store.
+func (d *Dispatcher) DispatchOnce(ctx context.Context) (int, error) {
+	return d.dispatch(ctx, nil)
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, stop <-chan struct{}) (int, error) {
+	messages, err := d.Store.Due(ctx, d.now(), d.Options.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, message := range messages {
+		select {
+		case <-stop:
+			return i, ErrDispatcherStopped
+		default:
+		}
+		if err := d.send(ctx, message); err != nil {
+			return i + 1, err
+		}
+	}
+	return len(messages), nil
+}
+
+// send attempts one message and records the outcome. The returned error is
+// from the store; send failures are recorded on the message.
+func (d *Dispatcher) send(ctx context.Context, message Message) error {
+	response, err := d.Emails.SendEmail(unsend.WithIdempotencyKey(ctx, message.Id), message.Request)
+	if err == nil {
+		d.log(ctx, slog.LevelInfo, "outbox message sent", "id", message.Id, "email_id", response.EmailId)
+		return d.Store.Complete(ctx, message.Id)
+	}
+	if ctx.Err() != nil {
+		// Interrupted by shutdown, so this attempt doesn't count.
+		return nil
+	}
+
+	message.Attempts++
+	message.LastError = err.Error()
+
+	if message.Attempts >= d.Options.MaxAttempts || permanent(err) {
+		d.log(ctx, slog.LevelWarn, "outbox message dead-lettered", "id", message.Id, "attempts", message.Attempts, "error", err)
+		if err := d.Store.DeadLetter(ctx, message); err != nil {
+			return err
+		}
+		if d.Options.OnDeadLetter != nil {
+			d.Options.OnDeadLetter(message)
+		}
+		return nil
+	}
+
+	message.NextAttemptAt = d.now().Add(d.backoff(message.Attempts))
+	d.log(ctx, slog.LevelWarn, "outbox message failed", "id", message.Id, "attempts", message.Attempts, "error", err)
+	return d.Store.Retry(ctx, message)
+}
+
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.Options.InitialBackoff << (attempts - 1)
+	if delay <= 0 || delay > d.Options.MaxBackoff {
+		return d.Options.MaxBackoff
+	}
+	return delay
+}
+
+func (d *Dispatcher) log(ctx context.Context, level slog.Level, msg string, args ...any) {
+	if d.Options.Logger != nil {
+		d.Options.Logger.Log(ctx, level, msg, args...)
+	}
+}
+
+// permanent reports whether the email failed in a way a retry won't fix:
+// rejected by the API, or refused by the client before it was sent, such as
+// for failing validation. A 409 means the API is still handling an earlier
+// attempt with the same key.
+func permanent(err error) bool {
+	var apiErr *unsend.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+		return false
+	}
+	return !unsend.IsTemporary(err)
+}
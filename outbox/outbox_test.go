@@ -0,0 +1,274 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/QGeeDev/unsend-go"
+	"github.com/QGeeDev/unsend-go/outbox"
+)
+
+type fakeEmails struct {
+	unsend.Emails
+
+	mu       sync.Mutex
+	keys     []string
+	failures map[string][]error
+}
+
+func (f *fakeEmails) SendEmail(ctx context.Context, request unsend.SendEmailRequest) (*unsend.EmailIdResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key, _ := unsend.IdempotencyKeyFromContext(ctx)
+	f.keys = append(f.keys, key)
+
+	if errs := f.failures[request.Subject]; len(errs) > 0 {
+		f.failures[request.Subject] = errs[1:]
+		return nil, errs[0]
+	}
+	return &unsend.EmailIdResponse{EmailId: "email-" + request.Subject}, nil
+}
+
+func (f *fakeEmails) sent() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.keys...)
+}
+
+func email(subject string) unsend.SendEmailRequest {
+	return unsend.SendEmailRequest{
+		To:      []string{"a@b.c"},
+		From:    "test@unsend.dev",
+		Subject: subject,
+		Text:    "Hello, World!",
+	}
+}
+
+func newMessage(t *testing.T, subject string) outbox.Message {
+	t.Helper()
+	message, err := outbox.NewMessage(email(subject))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return message
+}
+
+func stores(t *testing.T) map[string]outbox.Store {
+	fileStore, err := outbox.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[string]outbox.Store{
+		"Memory": outbox.NewMemoryStore(),
+		"File":   fileStore,
+	}
+}
+
+func TestStores(t *testing.T) {
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			first := newMessage(t, "first")
+			later := newMessage(t, "later")
+			second := newMessage(t, "second")
+
+			now := time.Now()
+			first.CreatedAt = now.Add(-2 * time.Minute)
+			later.NextAttemptAt = now.Add(time.Hour)
+			second.CreatedAt = now.Add(-time.Minute)
+
+			for _, message := range []outbox.Message{first, later, second} {
+				if err := store.Enqueue(ctx, message); err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+			}
+
+			due, err := store.Due(ctx, now, 10)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(due) != 2 || due[0].Id != first.Id || due[1].Id != second.Id {
+				t.Fatalf("expected first and second due in order, got %+v", due)
+			}
+			if due[0].Request.Subject != "first" {
+				t.Errorf("expected request to round trip, got %+v", due[0].Request)
+			}
+
+			if err := store.Complete(ctx, first.Id); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if err := store.Complete(ctx, first.Id); !errors.Is(err, outbox.ErrMessageNotFound) {
+				t.Errorf("expected ErrMessageNotFound, got %v", err)
+			}
+
+			second.Attempts = 1
+			second.LastError = "boom"
+			if err := store.DeadLetter(ctx, second); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			dead, _ := store.DeadLetters(ctx)
+			if len(dead) != 1 || dead[0].LastError != "boom" {
+				t.Errorf("expected second in dead letters, got %+v", dead)
+			}
+
+			due, _ = store.Due(ctx, now.Add(2*time.Hour), 10)
+			if len(due) != 1 || due[0].Id != later.Id {
+				t.Errorf("expected only later to remain, got %+v", due)
+			}
+		})
+	}
+}
+
+func TestFileStoreSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := outbox.NewFileStore(dir)
+	message := newMessage(t, "durable")
+	if err := store.Enqueue(context.Background(), message); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := outbox.NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	due, _ := reopened.Due(context.Background(), time.Now(), 10)
+	if len(due) != 1 || due[0].Id != message.Id {
+		t.Errorf("expected message to survive reopening the store, got %+v", due)
+	}
+}
+
+func TestFileStoreDueReadsOnlyDueMessages(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := outbox.NewFileStore(dir)
+	ctx := context.Background()
+
+	ready := newMessage(t, "ready")
+	later := newMessage(t, "later")
+	now := time.Now()
+	later.NextAttemptAt = now.Add(time.Hour)
+	for _, message := range []outbox.Message{ready, later} {
+		if err := store.Enqueue(ctx, message); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Due must not open a message that isn't due yet.
+	if err := os.WriteFile(filepath.Join(dir, "pending", later.Id+".json"), []byte("not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	due, err := store.Due(ctx, now, 10)
+	if err != nil || len(due) != 1 || due[0].Id != ready.Id || due[0].Request.Subject != "ready" {
+		t.Errorf("expected only the ready message in full, got %+v, %v", due, err)
+	}
+	if _, err := store.Due(ctx, now.Add(2*time.Hour), 10); err == nil {
+		t.Errorf("expected an error once the broken message is due")
+	}
+}
+
+func TestDispatcherRetriesAndDeadLetters(t *testing.T) {
+	timeout := fmt.Errorf("request failed: %w", &url.Error{Op: "Post", URL: "https://app.unsend.dev/api/v1/emails", Err: errors.New("i/o timeout")})
+	emails := &fakeEmails{failures: map[string][]error{
+		"flaky":      {timeout, &unsend.APIError{StatusCode: http.StatusServiceUnavailable}},
+		"broken":     {timeout, timeout, timeout},
+		"rejected":   {&unsend.APIError{StatusCode: http.StatusBadRequest, Body: `{"error": "bad"}`}},
+		"invalid":    {fmt.Errorf("[ERROR]: SendEmailRequest not valid; %w", &unsend.ValidationError{Errors: []string{"'From' is required"}})},
+		"unverified": {&unsend.SenderDomainError{Domain: "unsend.dev", Registered: true, Status: unsend.DOMAIN_STATUS_PENDING}},
+		"in-flight":  {&unsend.APIError{StatusCode: http.StatusConflict}},
+	}}
+
+	var deadLettered []string
+	store := outbox.NewMemoryStore()
+	dispatcher := outbox.NewDispatcher(store, emails, outbox.DispatcherOptions{
+		MaxAttempts:    3,
+		InitialBackoff: time.Nanosecond,
+		OnDeadLetter: func(message outbox.Message) {
+			deadLettered = append(deadLettered, message.Request.Subject)
+		},
+	})
+
+	ctx := context.Background()
+	flaky, _ := dispatcher.Enqueue(ctx, email("flaky"))
+	for _, subject := range []string{"broken", "rejected", "invalid", "unverified", "in-flight"} {
+		dispatcher.Enqueue(ctx, email(subject))
+	}
+
+	for i := 0; i < 3; i++ {
+		time.Sleep(time.Millisecond)
+		if _, err := dispatcher.DispatchOnce(ctx); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	if due, _ := store.Due(ctx, time.Now().Add(time.Hour), 10); len(due) != 0 {
+		t.Errorf("expected the outbox to be drained, got %+v", due)
+	}
+
+	dead, _ := store.DeadLetters(ctx)
+	if len(dead) != 4 || len(deadLettered) != 4 {
+		t.Fatalf("expected broken, rejected, invalid and unverified to be dead-lettered, got %+v", dead)
+	}
+	for _, message := range dead {
+		switch message.Request.Subject {
+		case "broken":
+			if message.Attempts != 3 {
+				t.Errorf("expected broken to fail 3 times, got %d", message.Attempts)
+			}
+		case "rejected", "invalid", "unverified":
+			if message.Attempts != 1 {
+				t.Errorf("expected %s to be dead-lettered on the first attempt, got %d", message.Request.Subject, message.Attempts)
+			}
+		}
+	}
+
+	flakyKeys := 0
+	for _, key := range emails.sent() {
+		if key == flaky.Id {
+			flakyKeys++
+		}
+	}
+	if flakyKeys != 3 {
+		t.Errorf("expected every attempt for flaky to use its Id as the idempotency key, got %d", flakyKeys)
+	}
+}
+
+func TestDispatcherRunAndShutdown(t *testing.T) {
+	emails := &fakeEmails{}
+	store := outbox.NewMemoryStore()
+	dispatcher := outbox.NewDispatcher(store, emails, outbox.DispatcherOptions{PollInterval: time.Hour})
+
+	result := make(chan error, 1)
+	go func() {
+		result <- dispatcher.Run(context.Background())
+	}()
+
+	if _, err := dispatcher.Enqueue(context.Background(), email("woken")); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(emails.sent()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if len(emails.sent()) != 1 {
+		t.Fatalf("expected Enqueue to wake the dispatcher")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := dispatcher.Shutdown(ctx); err != nil {
+		t.Fatalf("expected clean shutdown, got %v", err)
+	}
+	if err := <-result; err != nil {
+		t.Errorf("expected Run to return nil after Shutdown, got %v", err)
+	}
+}
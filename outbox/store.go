@@ -0,0 +1,398 @@
+// Package outbox queues emails in a durable store and sends them later through
+// the Unsend API, so an email enqueued alongside other work is delivered at
+// least once even if the process crashes before it is sent.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+var ErrMessageNotFound = errors.New("outbox message not found")
+
+// Message is an email waiting in the outbox. Id doubles as the idempotency key
+// for every attempt, so a resend after a crash is deduplicated by the API.
+type Message struct {
+	Id            string                  `json:"id"`
+	Request       unsend.SendEmailRequest `json:"request"`
+	CreatedAt     time.Time               `json:"createdAt"`
+	Attempts      int                     `json:"attempts"`
+	NextAttemptAt time.Time               `json:"nextAttemptAt"`
+	LastError     string                  `json:"lastError,omitempty"`
+}
+
+func NewMessage(request unsend.SendEmailRequest) (Message, error) {
+	id, err := unsend.NewIdempotencyKey()
+	if err != nil {
+		return Message{}, err
+	}
+
+	now := time.Now()
+	return Message{
+		Id:            id,
+		Request:       request,
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	}, nil
+}
+
+// Store persists outbox messages. Implementations backed by an application
+// database let callers enqueue inside their own transactions.
+type Store interface {
+	Enqueue(ctx context.Context, message Message) error
+	// Due returns up to limit pending messages whose NextAttemptAt is not
+	// after now, oldest first.
+	Due(ctx context.Context, now time.Time, limit int) ([]Message, error)
+	// Retry saves a pending message after a failed attempt.
+	Retry(ctx context.Context, message Message) error
+	// Complete removes a message that was sent.
+	Complete(ctx context.Context, id string) error
+	// DeadLetter moves a message that will not be retried out of the queue.
+	DeadLetter(ctx context.Context, message Message) error
+	DeadLetters(ctx context.Context) ([]Message, error)
+}
+
+type MemoryStore struct {
+	mu      sync.Mutex
+	pending map[string]Message
+	dead    map[string]Message
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		pending: map[string]Message{},
+		dead:    map[string]Message{},
+	}
+}
+
+func (s *MemoryStore) Enqueue(ctx context.Context, message Message) error {
+	if message.Id == "" {
+		return fmt.Errorf("[ERROR]: outbox message has no Id")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending[message.Id] = message
+	return nil
+}
+
+func (s *MemoryStore) Due(ctx context.Context, now time.Time, limit int) ([]Message, error) {
+	s.mu.Lock()
+	messages := make([]Message, 0, len(s.pending))
+	for _, message := range s.pending {
+		messages = append(messages, message)
+	}
+	s.mu.Unlock()
+
+	return due(messages, now, limit), nil
+}
+
+func (s *MemoryStore) Retry(ctx context.Context, message Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.pending[message.Id]; !ok {
+		return ErrMessageNotFound
+	}
+	s.pending[message.Id] = message
+	return nil
+}
+
+func (s *MemoryStore) Complete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.pending[id]; !ok {
+		return ErrMessageNotFound
+	}
+	delete(s.pending, id)
+	return nil
+}
+
+func (s *MemoryStore) DeadLetter(ctx context.Context, message Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.pending, message.Id)
+	s.dead[message.Id] = message
+	return nil
+}
+
+func (s *MemoryStore) DeadLetters(ctx context.Context) ([]Message, error) {
+	s.mu.Lock()
+	messages := make([]Message, 0, len(s.dead))
+	for _, message := range s.dead {
+		messages = append(messages, message)
+	}
+	s.mu.Unlock()
+
+	sortMessages(messages)
+	return messages, nil
+}
+
+// FileStore keeps one JSON file per message under Dir/pending and Dir/dead.
+// Files are written to a temporary name, synced and renamed into place so a
+// crash never leaves a partially written message. Pending messages are
+// indexed in memory so Due only reads the messages it returns, which assumes
+// the FileStore is the only writer to Dir.
+type FileStore struct {
+	Dir string
+
+	mu      sync.Mutex
+	pending map[string]Message
+}
+
+func NewFileStore(dir string) (*FileStore, error) {
+	for _, sub := range []string{"pending", "dead"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create outbox directory: %w", err)
+		}
+	}
+	return &FileStore{Dir: dir}, nil
+}
+
+func (s *FileStore) Enqueue(ctx context.Context, message Message) error {
+	if message.Id == "" {
+		return fmt.Errorf("[ERROR]: outbox message has no Id")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.loadPending(); err != nil {
+		return err
+	}
+	if err := s.write("pending", message); err != nil {
+		return err
+	}
+	s.pending[message.Id] = indexed(message)
+	return nil
+}
+
+func (s *FileStore) Due(ctx context.Context, now time.Time, limit int) ([]Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.loadPending(); err != nil {
+		return nil, err
+	}
+
+	candidates := make([]Message, 0, len(s.pending))
+	for _, message := range s.pending {
+		candidates = append(candidates, message)
+	}
+
+	selected := due(candidates, now, limit)
+	for i, message := range selected {
+		full, err := readMessage(s.path("pending", message.Id))
+		if err != nil {
+			return nil, err
+		}
+		selected[i] = full
+	}
+	return selected, nil
+}
+
+func (s *FileStore) Retry(ctx context.Context, message Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.loadPending(); err != nil {
+		return err
+	}
+	if _, ok := s.pending[message.Id]; !ok {
+		return ErrMessageNotFound
+	}
+	if err := s.write("pending", message); err != nil {
+		return err
+	}
+	s.pending[message.Id] = indexed(message)
+	return nil
+}
+
+func (s *FileStore) Complete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := os.Remove(s.path("pending", id))
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrMessageNotFound
+	}
+	if err != nil {
+		return err
+	}
+	delete(s.pending, id)
+	return nil
+}
+
+func (s *FileStore) DeadLetter(ctx context.Context, message Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.write("dead", message); err != nil {
+		return err
+	}
+	if err := os.Remove(s.path("pending", message.Id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	delete(s.pending, message.Id)
+	return nil
+}
+
+func (s *FileStore) DeadLetters(ctx context.Context) ([]Message, error) {
+	s.mu.Lock()
+	messages, err := s.read("dead")
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	sortMessages(messages)
+	return messages, nil
+}
+
+// loadPending reads the pending directory into the index the first time it is
+// needed.
+func (s *FileStore) loadPending() error {
+	if s.pending != nil {
+		return nil
+	}
+
+	messages, err := s.read("pending")
+	if err != nil {
+		return err
+	}
+	s.pending = make(map[string]Message, len(messages))
+	for _, message := range messages {
+		s.pending[message.Id] = indexed(message)
+	}
+	return nil
+}
+
+// indexed keeps the fields of message that Due sorts and filters on.
+func indexed(message Message) Message {
+	return Message{Id: message.Id, CreatedAt: message.CreatedAt, NextAttemptAt: message.NextAttemptAt}
+}
+
+func (s *FileStore) path(state, id string) string {
+	return filepath.Join(s.Dir, state, id+".json")
+}
+
+func (s *FileStore) write(state string, message Message) error {
+	if strings.ContainsAny(message.Id, `/\`) || strings.HasPrefix(message.Id, ".") {
+		return fmt.Errorf("[ERROR]: invalid outbox message Id '%s'", message.Id)
+	}
+
+	content, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Join(s.Dir, state), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), s.path(state, message.Id)); err != nil {
+		return err
+	}
+	return syncDir(filepath.Join(s.Dir, state))
+}
+
+// syncDir makes a rename in dir durable. Windows can't sync a directory, so
+// it is skipped there.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return f.Sync()
+}
+
+func (s *FileStore) read(state string) ([]Message, error) {
+	entries, err := os.ReadDir(filepath.Join(s.Dir, state))
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]Message, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		message, err := readMessage(filepath.Join(s.Dir, state, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+func readMessage(path string) (Message, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Message{}, err
+	}
+
+	var message Message
+	if err := json.Unmarshal(content, &message); err != nil {
+		return Message{}, fmt.Errorf("failed to read outbox message %s: %w", filepath.Base(path), err)
+	}
+	return message, nil
+}
+
+func due(messages []Message, now time.Time, limit int) []Message {
+	sortMessages(messages)
+
+	result := make([]Message, 0, min(len(messages), max(limit, 0)))
+	for _, message := range messages {
+		if len(result) >= limit {
+			break
+		}
+		if !message.NextAttemptAt.After(now) {
+			result = append(result, message)
+		}
+	}
+	return result
+}
+
+func sortMessages(messages []Message) {
+	sort.SliceStable(messages, func(i, j int) bool {
+		if messages[i].CreatedAt.Equal(messages[j].CreatedAt) {
+			return messages[i].Id < messages[j].Id
+		}
+		return messages[i].CreatedAt.Before(messages[j].CreatedAt)
+	})
+}
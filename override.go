@@ -0,0 +1,144 @@
+package unsend
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/mail"
+	"strings"
+)
+
+// RecipientOverride sends every email to fixed addresses instead of its real
+// recipients, for staging environments that should only ever reach a QA inbox.
+// The original recipients are kept in a subject prefix such as
+// "[To: a@example.com | Cc: b@example.com] Welcome", since the API has no
+// field for custom headers.
+type RecipientOverride struct {
+	// To replaces the To addresses and must not be empty. Cc and Bcc replace
+	// the Cc and Bcc addresses; when empty the email has none.
+	To  []string
+	Cc  []string
+	Bcc []string
+
+	// PlusAddressing sends to one tagged address per original To recipient,
+	// so qa@corp.com becomes qa+alice=example.com@corp.com for
+	// alice@example.com.
+	PlusAddressing bool
+
+	// OmitSubjectPrefix leaves the subject alone. Emails without a subject,
+	// such as template emails, never get a prefix.
+	OmitSubjectPrefix bool
+}
+
+// apply rewrites the recipients of a SendEmail request and refuses campaign
+// sends, whose recipients can't be rewritten. Other requests are left alone.
+func (o *RecipientOverride) apply(c *Client, req *http.Request) error {
+	if len(o.To) > 0 && isCampaignSend(req) {
+		return ErrCampaignSendRestricted
+	}
+	if !isSendEmail(req) || len(o.To) == 0 {
+		return nil
+	}
+
+	var email SendEmailRequest
+	if err := json.Unmarshal(requestBody(req), &email); err != nil {
+		return err
+	}
+
+	prefix := originalRecipients(email)
+
+	to := o.To
+	if o.PlusAddressing {
+		to = nil
+		for _, address := range o.To {
+			for _, original := range email.To {
+				to = appendUnique(to, plusAddress(address, original))
+			}
+		}
+		if len(to) == 0 {
+			to = o.To
+		}
+	}
+
+	email.To = to
+	email.Cc = o.Cc
+	email.Bcc = o.Bcc
+	if !o.OmitSubjectPrefix && email.Subject != "" && prefix != "" {
+		email.Subject = "[" + prefix + "] " + email.Subject
+	}
+
+	body, err := json.Marshal(email)
+	if err != nil {
+		return err
+	}
+	setRequestBody(req, body)
+
+	if c.Logger != nil {
+		c.Logger.DebugContext(req.Context(), "unsend recipients overridden",
+			"original_recipients", prefix, "to", strings.Join(email.To, ", "))
+	}
+
+	return nil
+}
+
+func originalRecipients(email SendEmailRequest) string {
+	var parts []string
+	for _, field := range []struct {
+		name      string
+		addresses []string
+	}{
+		{"To", email.To},
+		{"Cc", email.Cc},
+		{"Bcc", email.Bcc},
+	} {
+		if len(field.addresses) > 0 {
+			parts = append(parts, field.name+": "+strings.Join(field.addresses, ", "))
+		}
+	}
+	return strings.Join(parts, " | ")
+}
+
+// plusAddress tags address with original, e.g. qa@corp.com and
+// alice@example.com give qa+alice=example.com@corp.com.
+func plusAddress(address, original string) string {
+	local, domain, ok := strings.Cut(address, "@")
+	if !ok {
+		return address
+	}
+
+	if parsed, err := mail.ParseAddress(original); err == nil {
+		original = parsed.Address
+	}
+
+	tag := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		case r == '@':
+			return '='
+		default:
+			return '_'
+		}
+	}, strings.TrimSpace(original))
+
+	return local + "+" + tag + "@" + domain
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if strings.EqualFold(existing, value) {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// splitList splits a comma separated list, dropping empty items.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
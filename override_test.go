@@ -0,0 +1,95 @@
+package unsend_test
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+func TestRecipientOverride(t *testing.T) {
+	request := unsend.SendEmailRequest{
+		To:      []string{"alice@example.com", "Bob <bob@example.org>"},
+		Cc:      []string{"carol@example.com"},
+		Bcc:     []string{"audit@example.com"},
+		From:    "hello@unsend.dev",
+		Subject: "Welcome",
+		Text:    "Hi",
+	}
+
+	tests := []struct {
+		name            string
+		override        unsend.RecipientOverride
+		expectedTo      []interface{}
+		expectedCc      interface{}
+		expectedSubject string
+	}{
+		{
+			name:            "Fixed addresses",
+			override:        unsend.RecipientOverride{To: []string{"qa@corp.com"}, Cc: []string{"lead@corp.com"}},
+			expectedTo:      []interface{}{"qa@corp.com"},
+			expectedCc:      []interface{}{"lead@corp.com"},
+			expectedSubject: "[To: alice@example.com, Bob <bob@example.org> | Cc: carol@example.com | Bcc: audit@example.com] Welcome",
+		},
+		{
+			name:            "Plus addressing",
+			override:        unsend.RecipientOverride{To: []string{"qa@corp.com"}, PlusAddressing: true, OmitSubjectPrefix: true},
+			expectedTo:      []interface{}{"qa+alice=example.com@corp.com", "qa+bob=example.org@corp.com"},
+			expectedSubject: "Welcome",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newSandboxTestServer(t)
+
+			client, err := unsend.NewClient(
+				unsend.WithApiKey("test-api-key"),
+				unsend.WithBaseUrl(server.URL),
+				unsend.WithConfigFile(filepath.Join(t.TempDir(), "missing.toml")),
+				unsend.WithRecipientOverride(tt.override),
+			)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if _, err := client.Emails.SendEmail(context.Background(), request); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			got := requests()
+			if len(got) != 1 {
+				t.Fatalf("expected 1 request, got %d", len(got))
+			}
+			body := got[0].body
+			if !reflect.DeepEqual(body["to"], tt.expectedTo) {
+				t.Errorf("expected to %v, got %v", tt.expectedTo, body["to"])
+			}
+			if !reflect.DeepEqual(body["cc"], tt.expectedCc) || body["bcc"] != nil {
+				t.Errorf("expected cc %v and no bcc, got %v and %v", tt.expectedCc, body["cc"], body["bcc"])
+			}
+			if body["subject"] != tt.expectedSubject {
+				t.Errorf("expected subject %q, got %q", tt.expectedSubject, body["subject"])
+			}
+		})
+	}
+}
+
+func TestRecipientOverrideFromEnv(t *testing.T) {
+	t.Setenv(unsend.ENV_KEY_RECIPIENT_OVERRIDE, "qa@corp.com, qa2@corp.com")
+
+	client, err := unsend.NewClient(
+		unsend.WithApiKey("test-api-key"),
+		unsend.WithConfigFile(filepath.Join(t.TempDir(), "missing.toml")),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := &unsend.RecipientOverride{To: []string{"qa@corp.com", "qa2@corp.com"}}
+	if !reflect.DeepEqual(client.RecipientOverride, expected) {
+		t.Errorf("expected %+v, got %+v", expected, client.RecipientOverride)
+	}
+}
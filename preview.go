@@ -0,0 +1,363 @@
+package unsend
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const MODE_PREVIEW = "preview"
+const DEFAULT_PREVIEW_ADDR = "127.0.0.1:8025"
+
+const PREVIEW_STATUS_SENT = "SENT"
+const PREVIEW_STATUS_SCHEDULED = "SCHEDULED"
+const PREVIEW_STATUS_CANCELLED = "CANCELLED"
+
+// PreviewEmail is an email captured by PreviewEmails instead of being sent.
+type PreviewEmail struct {
+	Id        string           `json:"id"`
+	Status    string           `json:"status"`
+	CreatedAt time.Time        `json:"createdAt"`
+	Request   SendEmailRequest `json:"request"`
+}
+
+// PreviewEmails is an Emails implementation for local development. It keeps
+// every email in memory rather than calling the API, and serves a web UI for
+// viewing them as an http.Handler.
+type PreviewEmails struct {
+	DefaultFrom string
+
+	mu     sync.Mutex
+	emails []*PreviewEmail
+	nextId int
+
+	muxOnce sync.Once
+	mux     *http.ServeMux
+}
+
+func NewPreviewEmails() *PreviewEmails {
+	return &PreviewEmails{}
+}
+
+func (p *PreviewEmails) SendEmail(ctx context.Context, request SendEmailRequest) (*EmailIdResponse, error) {
+	if request.From == "" {
+		request.From = p.DefaultFrom
+	}
+
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("[ERROR]: SendEmailRequest not valid; %w", err)
+	}
+	request.moveScheduleAt()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.nextId++
+	email := &PreviewEmail{
+		Id:        "preview-" + strconv.Itoa(p.nextId),
+		Status:    PREVIEW_STATUS_SENT,
+		CreatedAt: time.Now().UTC(),
+		Request:   request,
+	}
+	if request.ScheduledAt != "" {
+		email.Status = PREVIEW_STATUS_SCHEDULED
+	}
+	p.emails = append(p.emails, email)
+
+	return &EmailIdResponse{EmailId: email.Id}, nil
+}
+
+func (p *PreviewEmails) GetEmail(ctx context.Context, request GetEmailRequest) (*GetEmailResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("[ERROR]: GetEmailRequest not valid; %w", err)
+	}
+
+	email, ok := p.Email(request.EmailId)
+	if !ok {
+		return nil, previewNotFound(request.EmailId)
+	}
+
+	createdAt := email.CreatedAt.Format(time.RFC3339)
+	return &GetEmailResponse{
+		Id:        email.Id,
+		To:        email.Request.To,
+		From:      email.Request.From,
+		Subject:   email.Request.Subject,
+		Html:      email.Request.Html,
+		Text:      email.Request.Text,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+		ReplyTo:   email.Request.ReplyTo,
+		Cc:        email.Request.Cc,
+		Bcc:       email.Request.Bcc,
+		EmailEvents: []EmailEvents{
+			{EmailId: email.Id, Status: email.Status, CreatedAt: createdAt},
+		},
+	}, nil
+}
+
+func (p *PreviewEmails) UpdateSchedule(ctx context.Context, request UpdateScheduleRequest) (*EmailIdResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("[ERROR]: UpdateScheduleRequest not valid; %w", err)
+	}
+
+	return p.update(request.EmailId, func(email *PreviewEmail) {
+		email.Request.ScheduledAt = request.ScheduledAt
+		email.Status = PREVIEW_STATUS_SCHEDULED
+	})
+}
+
+func (p *PreviewEmails) CancelSchedule(ctx context.Context, request CancelScheduleRequest) (*EmailIdResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("[ERROR]: CancelScheduleRequest not valid; %w", err)
+	}
+
+	return p.update(request.EmailId, func(email *PreviewEmail) {
+		email.Status = PREVIEW_STATUS_CANCELLED
+	})
+}
+
+func (p *PreviewEmails) update(id string, change func(*PreviewEmail)) (*EmailIdResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, email := range p.emails {
+		if email.Id == id {
+			change(email)
+			return &EmailIdResponse{EmailId: id}, nil
+		}
+	}
+	return nil, previewNotFound(id)
+}
+
+// Emails returns the captured emails, newest first.
+func (p *PreviewEmails) Emails() []PreviewEmail {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	emails := make([]PreviewEmail, 0, len(p.emails))
+	for i := len(p.emails) - 1; i >= 0; i-- {
+		emails = append(emails, *p.emails[i])
+	}
+	return emails
+}
+
+func (p *PreviewEmails) Email(id string) (PreviewEmail, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, email := range p.emails {
+		if email.Id == id {
+			return *email, true
+		}
+	}
+	return PreviewEmail{}, false
+}
+
+func (p *PreviewEmails) Clear() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.emails = nil
+}
+
+func previewNotFound(id string) error {
+	return &APIError{StatusCode: http.StatusNotFound, Body: `{"error":"email '` + id + `' not found"}`}
+}
+
+// ServeHTTP serves the preview UI:
+//
+//	GET  /                               list of captured emails
+//	GET  /emails/{id}                    email details
+//	GET  /emails/{id}/html               the HTML body
+//	GET  /emails/{id}/json               the SendEmailRequest as JSON
+//	GET  /emails/{id}/eml                the email as an .eml message
+//	GET  /emails/{id}/attachments/{n}    download an attachment
+//	GET  /api/emails                     all emails as JSON
+//	POST /clear                          delete all emails
+func (p *PreviewEmails) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.muxOnce.Do(func() {
+		p.mux = http.NewServeMux()
+		p.mux.HandleFunc("GET /{$}", p.serveList)
+		p.mux.HandleFunc("GET /api/emails", p.serveListJson)
+		p.mux.HandleFunc("POST /clear", p.serveClear)
+		p.mux.HandleFunc("GET /emails/{id}", p.withEmail(p.serveEmail))
+		p.mux.HandleFunc("GET /emails/{id}/html", p.withEmail(p.serveHtml))
+		p.mux.HandleFunc("GET /emails/{id}/json", p.withEmail(p.serveJson))
+		p.mux.HandleFunc("GET /emails/{id}/eml", p.withEmail(p.serveEml))
+		p.mux.HandleFunc("GET /emails/{id}/attachments/{index}", p.withEmail(p.serveAttachment))
+	})
+	p.mux.ServeHTTP(w, r)
+}
+
+func (p *PreviewEmails) withEmail(handler func(http.ResponseWriter, *http.Request, PreviewEmail)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		email, ok := p.Email(r.PathValue("id"))
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		handler(w, r, email)
+	}
+}
+
+func (p *PreviewEmails) serveList(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	previewTemplates.ExecuteTemplate(w, "list", p.Emails())
+}
+
+func (p *PreviewEmails) serveListJson(w http.ResponseWriter, r *http.Request) {
+	writePreviewJson(w, p.Emails())
+}
+
+func (p *PreviewEmails) serveClear(w http.ResponseWriter, r *http.Request) {
+	p.Clear()
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+func (p *PreviewEmails) serveEmail(w http.ResponseWriter, r *http.Request, email PreviewEmail) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	previewTemplates.ExecuteTemplate(w, "email", email)
+}
+
+func (p *PreviewEmails) serveHtml(w http.ResponseWriter, r *http.Request, email PreviewEmail) {
+	// The body is untrusted markup, so it is shown in a sandbox with no
+	// scripts and no access to the preview UI's origin.
+	w.Header().Set("Content-Security-Policy", "sandbox; script-src 'none'")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(email.Request.Html))
+}
+
+func (p *PreviewEmails) serveJson(w http.ResponseWriter, r *http.Request, email PreviewEmail) {
+	writePreviewJson(w, email.Request)
+}
+
+func (p *PreviewEmails) serveEml(w http.ResponseWriter, r *http.Request, email PreviewEmail) {
+	w.Header().Set("Content-Type", "message/rfc822")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": email.Id + ".eml"}))
+	email.Request.WriteMessage(w)
+}
+
+func (p *PreviewEmails) serveAttachment(w http.ResponseWriter, r *http.Request, email PreviewEmail) {
+	index, err := strconv.Atoi(r.PathValue("index"))
+	if err != nil || index < 0 || index >= len(email.Request.Attachments) {
+		http.NotFound(w, r)
+		return
+	}
+
+	attachment := email.Request.Attachments[index]
+	content, err := base64.StdEncoding.DecodeString(attachment.Content)
+	if err != nil {
+		http.Error(w, "attachment is not valid base64", http.StatusUnprocessableEntity)
+		return
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(attachment.Filename))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	w.Write(content)
+}
+
+func writePreviewJson(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
+
+// previewServer serves PreviewEmails on addr until Close is called.
+type previewServer struct {
+	server   *http.Server
+	listener net.Listener
+}
+
+func startPreviewServer(addr string, handler http.Handler) (*previewServer, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start preview server: %w", err)
+	}
+
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("unsend preview server stopped", "error", err)
+		}
+	}()
+
+	return &previewServer{server: server, listener: listener}, nil
+}
+
+func (s *previewServer) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
+
+var previewTemplates = template.Must(template.New("preview").Funcs(template.FuncMap{
+	"join": func(values []string) string { return strings.Join(values, ", ") },
+}).Parse(`
+{{define "head"}}<!doctype html>
+<html><head><meta charset="utf-8"><title>{{.}} · Unsend preview</title>
+<style>
+body{font-family:system-ui,sans-serif;margin:0;color:#222}
+header{background:#111;color:#fff;padding:.75rem 1.5rem;display:flex;justify-content:space-between;align-items:center}
+header a{color:#fff;text-decoration:none;font-weight:600}
+main{padding:1.5rem}
+table{border-collapse:collapse;width:100%}
+th,td{text-align:left;padding:.5rem;border-bottom:1px solid #eee}
+dt{font-weight:600;float:left;width:7rem}
+dd{margin:0 0 .4rem 7rem}
+iframe{width:100%;height:60vh;border:1px solid #ddd}
+pre{background:#f6f6f6;padding:1rem;white-space:pre-wrap}
+.status{font-size:.8rem;padding:.1rem .4rem;border-radius:3px;background:#eee}
+</style></head><body>
+<header><a href="/">Unsend preview</a>
+<form method="post" action="/clear"><button>Clear all</button></form></header><main>
+{{end}}
+
+{{define "foot"}}</main></body></html>{{end}}
+
+{{define "list"}}{{template "head" "Emails"}}
+{{if .}}<table>
+<tr><th>Subject</th><th>From</th><th>To</th><th>Status</th><th>Captured</th></tr>
+{{range .}}<tr>
+<td><a href="/emails/{{.Id}}">{{if .Request.Subject}}{{.Request.Subject}}{{else}}(no subject){{end}}</a></td>
+<td>{{.Request.From}}</td><td>{{join .Request.To}}</td>
+<td><span class="status">{{.Status}}</span></td>
+<td>{{.CreatedAt.Format "2006-01-02 15:04:05"}}</td>
+</tr>{{end}}
+</table>{{else}}<p>No emails yet. Anything sent with SendEmail will show up here.</p>{{end}}
+{{template "foot"}}{{end}}
+
+{{define "email"}}{{template "head" .Request.Subject}}
+<h2>{{if .Request.Subject}}{{.Request.Subject}}{{else}}(no subject){{end}}</h2>
+<dl>
+<dt>From</dt><dd>{{.Request.From}}</dd>
+<dt>To</dt><dd>{{join .Request.To}}</dd>
+{{with .Request.Cc}}<dt>Cc</dt><dd>{{join .}}</dd>{{end}}
+{{with .Request.Bcc}}<dt>Bcc</dt><dd>{{join .}}</dd>{{end}}
+{{with .Request.ReplyTo}}<dt>Reply-To</dt><dd>{{join .}}</dd>{{end}}
+{{with .Request.TemplateId}}<dt>Template</dt><dd>{{.}}</dd>{{end}}
+{{with .Request.ScheduledAt}}<dt>Scheduled</dt><dd>{{.}}</dd>{{end}}
+<dt>Status</dt><dd><span class="status">{{.Status}}</span></dd>
+<dt>Download</dt><dd><a href="/emails/{{.Id}}/json">JSON</a> · <a href="/emails/{{.Id}}/eml">.eml</a></dd>
+{{$id := .Id}}{{with .Request.Attachments}}<dt>Attachments</dt><dd>{{range $i, $a := .}}<a href="/emails/{{$id}}/attachments/{{$i}}">{{$a.Filename}}</a> {{end}}</dd>{{end}}
+</dl>
+{{if .Request.Html}}<h3>HTML</h3><iframe sandbox src="/emails/{{.Id}}/html"></iframe>{{end}}
+{{if .Request.Text}}<h3>Text</h3><pre>{{.Request.Text}}</pre>{{end}}
+{{template "foot"}}{{end}}
+`))
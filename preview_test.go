@@ -0,0 +1,144 @@
+package unsend_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+func TestPreviewMode(t *testing.T) {
+	t.Setenv(unsend.ENV_KEY_API_KEY, "")
+	t.Setenv(unsend.ENV_KEY_MODE, "")
+
+	client, err := unsend.NewClient(
+		unsend.WithConfigFile(filepath.Join(t.TempDir(), "missing.toml")),
+		unsend.WithPreviewMode("127.0.0.1:0"),
+	)
+	if err != nil {
+		t.Fatalf("expected preview mode to work without an API key, got %v", err)
+	}
+	defer client.Close()
+
+	response, err := client.Emails.SendEmail(context.Background(), unsend.SendEmailRequest{
+		To:      []string{"a@b.c"},
+		From:    "hello@unsend.dev",
+		Subject: "Preview me",
+		Html:    "<p>Hi <script>alert(1)</script></p>",
+		Attachments: []unsend.Attachments{
+			{Filename: "notes.txt", Content: base64.StdEncoding.EncodeToString([]byte("attached"))},
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	email, err := client.Emails.GetEmail(context.Background(), unsend.GetEmailRequest{EmailId: response.EmailId})
+	if err != nil || email.Subject != "Preview me" {
+		t.Fatalf("expected captured email from GetEmail, got %+v, %v", email, err)
+	}
+
+	base := "http://" + client.PreviewAddr
+	get := func(path string) (*http.Response, string) {
+		t.Helper()
+		resp, err := http.Get(base + path)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp, string(body)
+	}
+
+	if _, body := get("/"); !strings.Contains(body, "Preview me") || !strings.Contains(body, "/emails/"+response.EmailId) {
+		t.Errorf("expected list to link to the email, got %s", body)
+	}
+
+	if _, body := get("/emails/" + response.EmailId); !strings.Contains(body, "notes.txt") || !strings.Contains(body, "<iframe sandbox") {
+		t.Errorf("expected detail page with attachments and sandboxed HTML, got %s", body)
+	}
+
+	resp, body := get("/emails/" + response.EmailId + "/html")
+	if !strings.Contains(resp.Header.Get("Content-Security-Policy"), "sandbox") || !strings.Contains(body, "<p>Hi") {
+		t.Errorf("expected sandboxed HTML body, got %v %s", resp.Header, body)
+	}
+
+	if resp, body := get("/emails/" + response.EmailId + "/attachments/0"); body != "attached" || !strings.Contains(resp.Header.Get("Content-Disposition"), "notes.txt") {
+		t.Errorf("expected attachment download, got %s", body)
+	}
+
+	var request unsend.SendEmailRequest
+	if _, body := get("/emails/" + response.EmailId + "/json"); json.Unmarshal([]byte(body), &request) != nil || request.Subject != "Preview me" {
+		t.Errorf("expected raw JSON request, got %s", body)
+	}
+
+	if _, body := get("/emails/" + response.EmailId + "/eml"); !strings.Contains(body, "Subject: Preview me") {
+		t.Errorf("expected .eml download, got %s", body)
+	}
+
+	if resp, _ := get("/emails/missing"); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown email, got %d", resp.StatusCode)
+	}
+}
+
+func TestPreviewModeFromEnv(t *testing.T) {
+	t.Setenv(unsend.ENV_KEY_API_KEY, "")
+	t.Setenv(unsend.ENV_KEY_MODE, unsend.MODE_PREVIEW)
+	t.Setenv(unsend.ENV_KEY_PREVIEW_ADDR, "127.0.0.1:0")
+
+	client, err := unsend.NewClient(unsend.WithConfigFile(filepath.Join(t.TempDir(), "missing.toml")))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer client.Close()
+
+	if client.Preview == nil || client.Emails != client.Preview {
+		t.Errorf("expected UNSEND_MODE=preview to capture emails")
+	}
+}
+
+func TestPreviewModeDefaultAddrInUse(t *testing.T) {
+	t.Setenv(unsend.ENV_KEY_MODE, "")
+	t.Setenv(unsend.ENV_KEY_PREVIEW_ADDR, "")
+
+	var clients []*unsend.Client
+	defer func() {
+		for _, client := range clients {
+			client.Close()
+		}
+	}()
+
+	for i := 0; i < 2; i++ {
+		client, err := unsend.NewClient(
+			unsend.WithConfigFile(filepath.Join(t.TempDir(), "missing.toml")),
+			unsend.WithPreviewMode(""),
+		)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		clients = append(clients, client)
+	}
+
+	if clients[0].PreviewAddr == clients[1].PreviewAddr {
+		t.Errorf("expected the second client to fall back to a free port, both got %s", clients[0].PreviewAddr)
+	}
+}
+
+func TestPreviewEmailsClear(t *testing.T) {
+	preview := unsend.NewPreviewEmails()
+	preview.SendEmail(context.Background(), unsend.SendEmailRequest{To: []string{"a@b.c"}, From: "hello@unsend.dev", Text: "Hi"})
+
+	recorder := httptest.NewRecorder()
+	preview.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/clear", nil))
+
+	if recorder.Code != http.StatusSeeOther || len(preview.Emails()) != 0 {
+		t.Errorf("expected emails to be cleared, got %d and %d emails", recorder.Code, len(preview.Emails()))
+	}
+}
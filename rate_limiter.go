@@ -0,0 +1,320 @@
+package unsend
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const ENDPOINT_GROUP_EMAILS = "emails"
+const ENDPOINT_GROUP_CONTACTS = "contacts"
+const ENDPOINT_GROUP_DOMAINS = "domains"
+const ENDPOINT_GROUP_CAMPAIGNS = "campaigns"
+const ENDPOINT_GROUP_OTHER = "other"
+
+// RATE_LIMIT_SCOPE_HEADER set to RATE_LIMIT_SCOPE_GLOBAL on a response marks
+// a limit that covers every endpoint group rather than the one requested.
+const RATE_LIMIT_SCOPE_HEADER = "X-RateLimit-Scope"
+const RATE_LIMIT_SCOPE_GLOBAL = "global"
+
+const DEFAULT_RATE_DECREASE_FACTOR = 0.5
+const DEFAULT_RATE_RECOVERY_PER_SECOND = 0.02
+const DEFAULT_MIN_RATE_FRACTION = 0.05
+
+type RateLimit struct {
+	// Rate is the number of requests allowed per second.
+	Rate  float64
+	Burst int
+}
+
+type RateLimiterOptions struct {
+	// Global applies to every request. A zero Rate means unlimited.
+	Global RateLimit
+	// Groups adds limits per endpoint group (ENDPOINT_GROUP_EMAILS, ...).
+	Groups map[string]RateLimit
+	// DecreaseFactor multiplies the current rate on each 429. Defaults to 0.5.
+	DecreaseFactor float64
+	// RecoveryPerSecond is the fraction of the configured rate regained each
+	// second after throttling. Defaults to 0.02, a full recovery in ~50s.
+	RecoveryPerSecond float64
+	// MinRateFraction stops the rate dropping below this fraction of the
+	// configured rate. Defaults to 0.05.
+	MinRateFraction float64
+}
+
+type RateLimitStats struct {
+	ConfiguredRate float64
+	CurrentRate    float64
+	Tokens         float64
+	Throttled      int
+	BlockedUntil   time.Time
+}
+
+type RateLimiterStats struct {
+	Global RateLimitStats
+	Groups map[string]RateLimitStats
+}
+
+// RateLimiter is a token bucket limiter that slows down when the server
+// returns 429 or reports an exhausted rate limit, then recovers gradually.
+type RateLimiter struct {
+	options RateLimiterOptions
+
+	mu     sync.Mutex
+	global *adaptiveBucket
+	groups map[string]*adaptiveBucket
+	now    func() time.Time
+}
+
+type adaptiveBucket struct {
+	configured   float64
+	rate         float64
+	burst        float64
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+	throttled    int
+}
+
+func NewRateLimiter(options RateLimiterOptions) *RateLimiter {
+	if options.DecreaseFactor <= 0 || options.DecreaseFactor >= 1 {
+		options.DecreaseFactor = DEFAULT_RATE_DECREASE_FACTOR
+	}
+	if options.RecoveryPerSecond <= 0 {
+		options.RecoveryPerSecond = DEFAULT_RATE_RECOVERY_PER_SECOND
+	}
+	if options.MinRateFraction <= 0 || options.MinRateFraction > 1 {
+		options.MinRateFraction = DEFAULT_MIN_RATE_FRACTION
+	}
+
+	limiter := &RateLimiter{
+		options: options,
+		groups:  map[string]*adaptiveBucket{},
+		now:     time.Now,
+	}
+
+	start := limiter.now()
+	limiter.global = newAdaptiveBucket(options.Global, start)
+	for group, limit := range options.Groups {
+		limiter.groups[group] = newAdaptiveBucket(limit, start)
+	}
+
+	return limiter
+}
+
+func newAdaptiveBucket(limit RateLimit, now time.Time) *adaptiveBucket {
+	burst := float64(max(limit.Burst, 1))
+	return &adaptiveBucket{
+		configured: limit.Rate,
+		rate:       limit.Rate,
+		burst:      burst,
+		tokens:     burst,
+		last:       now,
+	}
+}
+
+// Wait blocks until a request to the endpoint group may be sent, or ctx is
+// done.
+func (l *RateLimiter) Wait(ctx context.Context, group string) error {
+	for {
+		l.mu.Lock()
+		now := l.now()
+		buckets := l.buckets(group)
+
+		var delay time.Duration
+		for _, bucket := range buckets {
+			l.refill(bucket, now)
+			delay = max(delay, bucket.delay(now))
+		}
+
+		if delay == 0 {
+			for _, bucket := range buckets {
+				if bucket.configured > 0 {
+					bucket.tokens--
+				}
+			}
+			l.mu.Unlock()
+			return nil
+		}
+		l.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Observe adapts the limits for the endpoint group from a response: a 429
+// lowers the rate, and Retry-After or an exhausted X-RateLimit-Remaining
+// pauses requests until the server's reset time. A group with a limit of its
+// own takes the response alone, so one throttled group doesn't slow the
+// others, unless the server marks the limit as global.
+func (l *RateLimiter) Observe(group string, resp *http.Response) {
+	if resp == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	pauseUntil := rateLimitPause(resp.Header, now)
+	throttled := resp.StatusCode == http.StatusTooManyRequests
+
+	if !throttled && pauseUntil.IsZero() {
+		return
+	}
+
+	buckets := []*adaptiveBucket{l.global}
+	if bucket, ok := l.groups[group]; ok {
+		buckets = []*adaptiveBucket{bucket}
+		if strings.EqualFold(firstHeader(resp.Header, RATE_LIMIT_SCOPE_HEADER), RATE_LIMIT_SCOPE_GLOBAL) {
+			buckets = append(buckets, l.global)
+		}
+	}
+
+	for _, bucket := range buckets {
+		l.refill(bucket, now)
+		if throttled {
+			bucket.throttled++
+			if bucket.configured > 0 {
+				floor := bucket.configured * l.options.MinRateFraction
+				bucket.rate = math.Max(bucket.rate*l.options.DecreaseFactor, floor)
+				bucket.tokens = math.Min(bucket.tokens, 0)
+			}
+		}
+		if pauseUntil.After(bucket.blockedUntil) {
+			bucket.blockedUntil = pauseUntil
+		}
+	}
+}
+
+func (l *RateLimiter) Stats() RateLimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.refill(l.global, now)
+	stats := RateLimiterStats{
+		Global: l.global.stats(),
+		Groups: map[string]RateLimitStats{},
+	}
+	for group, bucket := range l.groups {
+		l.refill(bucket, now)
+		stats.Groups[group] = bucket.stats()
+	}
+	return stats
+}
+
+func (l *RateLimiter) buckets(group string) []*adaptiveBucket {
+	buckets := []*adaptiveBucket{l.global}
+	if bucket, ok := l.groups[group]; ok {
+		buckets = append(buckets, bucket)
+	}
+	return buckets
+}
+
+func (l *RateLimiter) refill(bucket *adaptiveBucket, now time.Time) {
+	elapsed := now.Sub(bucket.last).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	bucket.last = now
+
+	if bucket.configured <= 0 {
+		return
+	}
+
+	bucket.rate = math.Min(bucket.configured, bucket.rate+bucket.configured*l.options.RecoveryPerSecond*elapsed)
+	bucket.tokens = math.Min(bucket.burst, bucket.tokens+bucket.rate*elapsed)
+}
+
+func (b *adaptiveBucket) delay(now time.Time) time.Duration {
+	var delay time.Duration
+	if now.Before(b.blockedUntil) {
+		delay = b.blockedUntil.Sub(now)
+	}
+	if b.configured > 0 && b.tokens < 1 {
+		delay = max(delay, time.Duration((1-b.tokens)/b.rate*float64(time.Second)))
+	}
+	return delay
+}
+
+func (b *adaptiveBucket) stats() RateLimitStats {
+	return RateLimitStats{
+		ConfiguredRate: b.configured,
+		CurrentRate:    b.rate,
+		Tokens:         b.tokens,
+		Throttled:      b.throttled,
+		BlockedUntil:   b.blockedUntil,
+	}
+}
+
+// rateLimitPause returns when the server says requests may resume, from
+// Retry-After or an X-RateLimit-Remaining of zero with X-RateLimit-Reset.
+func rateLimitPause(header http.Header, now time.Time) time.Time {
+	if retryAfter := header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.ParseFloat(retryAfter, 64); err == nil {
+			return now.Add(time.Duration(seconds * float64(time.Second)))
+		}
+		if at, err := http.ParseTime(retryAfter); err == nil {
+			return at
+		}
+	}
+
+	remaining := firstHeader(header, "X-RateLimit-Remaining", "RateLimit-Remaining")
+	reset := firstHeader(header, "X-RateLimit-Reset", "RateLimit-Reset")
+	if remaining != "0" || reset == "" {
+		return time.Time{}
+	}
+
+	value, err := strconv.ParseFloat(reset, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	// Large values are Unix timestamps, small ones are seconds from now.
+	if value > 1e9 {
+		return time.Unix(0, int64(value*float64(time.Second)))
+	}
+	return now.Add(time.Duration(value * float64(time.Second)))
+}
+
+func firstHeader(header http.Header, names ...string) string {
+	for _, name := range names {
+		if value := strings.TrimSpace(header.Get(name)); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// endpointGroup maps a request path such as /api/v1/contactBooks/x/contacts
+// to the group used for rate limits.
+func endpointGroup(path string) string {
+	_, rest, ok := strings.Cut(path, "v1/")
+	if !ok {
+		return ENDPOINT_GROUP_OTHER
+	}
+	resource, _, _ := strings.Cut(rest, "/")
+
+	switch resource {
+	case "emails":
+		return ENDPOINT_GROUP_EMAILS
+	case "contactBooks":
+		return ENDPOINT_GROUP_CONTACTS
+	case "domains":
+		return ENDPOINT_GROUP_DOMAINS
+	case "campaigns":
+		return ENDPOINT_GROUP_CAMPAIGNS
+	default:
+		return ENDPOINT_GROUP_OTHER
+	}
+}
@@ -0,0 +1,165 @@
+package unsend_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+func TestRateLimiterWait(t *testing.T) {
+	limiter := unsend.NewRateLimiter(unsend.RateLimiterOptions{
+		Global: unsend.RateLimit{Rate: 100, Burst: 1},
+	})
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		if err := limiter.Wait(context.Background(), unsend.ENDPOINT_GROUP_EMAILS); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("expected 6 requests at 100/s to take at least 40ms, took %s", elapsed)
+	}
+}
+
+func TestRateLimiterWaitRespectsContext(t *testing.T) {
+	limiter := unsend.NewRateLimiter(unsend.RateLimiterOptions{
+		Groups: map[string]unsend.RateLimit{
+			unsend.ENDPOINT_GROUP_EMAILS: {Rate: 0.1, Burst: 1},
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := limiter.Wait(ctx, unsend.ENDPOINT_GROUP_EMAILS); err != nil {
+		t.Fatalf("expected first request to pass, got %v", err)
+	}
+	if err := limiter.Wait(ctx, unsend.ENDPOINT_GROUP_DOMAINS); err != nil {
+		t.Fatalf("expected other groups to be unaffected, got %v", err)
+	}
+	if err := limiter.Wait(ctx, unsend.ENDPOINT_GROUP_EMAILS); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestRateLimiterAdaptsToServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/emails/throttled":
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error": "rate limited"}`))
+		case "/api/v1/emails/exhausted":
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", "0.05")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "exhausted"}`))
+		default:
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "ok"}`))
+		}
+	}))
+	defer server.Close()
+
+	limiter := unsend.NewRateLimiter(unsend.RateLimiterOptions{
+		Global:            unsend.RateLimit{Rate: 1000, Burst: 10},
+		RecoveryPerSecond: 10,
+	})
+
+	client := &unsend.Client{
+		Client:      &http.Client{},
+		RateLimiter: limiter,
+	}
+	client.BaseUrl, _ = url.Parse(server.URL)
+	client.Emails = &unsend.EmailsImpl{Client: client}
+
+	ctx := context.Background()
+	if _, err := client.Emails.GetEmail(ctx, unsend.GetEmailRequest{EmailId: "throttled"}); err == nil {
+		t.Fatalf("expected 429 to be returned as an error")
+	}
+
+	stats := limiter.Stats()
+	if stats.Global.Throttled != 1 {
+		t.Errorf("expected 1 throttled response, got %d", stats.Global.Throttled)
+	}
+	if stats.Global.CurrentRate >= 1000 {
+		t.Errorf("expected rate to drop after 429, got %f", stats.Global.CurrentRate)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	recovered := limiter.Stats().Global.CurrentRate
+	if recovered <= stats.Global.CurrentRate {
+		t.Errorf("expected rate to recover over time, went from %f to %f", stats.Global.CurrentRate, recovered)
+	}
+
+	if _, err := client.Emails.GetEmail(ctx, unsend.GetEmailRequest{EmailId: "exhausted"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	start := time.Now()
+	if _, err := client.Emails.GetEmail(ctx, unsend.GetEmailRequest{EmailId: "ok"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("expected request to wait for the rate limit reset, took %s", elapsed)
+	}
+}
+
+func TestRateLimiterObserveKeepsGroupLimitsApart(t *testing.T) {
+	tests := []struct {
+		name             string
+		group            string
+		scope            string
+		expectedGlobal   int
+		expectedEmails   int
+		expectedContacts int
+	}{
+		{name: "Group with its own limit", group: unsend.ENDPOINT_GROUP_EMAILS, expectedEmails: 1},
+		{name: "Global limit signalled", group: unsend.ENDPOINT_GROUP_EMAILS, scope: "global", expectedGlobal: 1, expectedEmails: 1},
+		{name: "Group without its own limit", group: unsend.ENDPOINT_GROUP_DOMAINS, expectedGlobal: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := unsend.NewRateLimiter(unsend.RateLimiterOptions{
+				Global: unsend.RateLimit{Rate: 100, Burst: 10},
+				Groups: map[string]unsend.RateLimit{
+					unsend.ENDPOINT_GROUP_EMAILS:   {Rate: 10, Burst: 1},
+					unsend.ENDPOINT_GROUP_CONTACTS: {Rate: 10, Burst: 1},
+				},
+			})
+
+			header := http.Header{"Retry-After": {"60"}}
+			if tt.scope != "" {
+				header.Set(unsend.RATE_LIMIT_SCOPE_HEADER, tt.scope)
+			}
+			limiter.Observe(tt.group, &http.Response{StatusCode: http.StatusTooManyRequests, Header: header})
+
+			stats := limiter.Stats()
+			if stats.Global.Throttled != tt.expectedGlobal || stats.Groups[unsend.ENDPOINT_GROUP_EMAILS].Throttled != tt.expectedEmails ||
+				stats.Groups[unsend.ENDPOINT_GROUP_CONTACTS].Throttled != tt.expectedContacts {
+				t.Errorf("expected global %d, emails %d and contacts %d throttled, got %+v", tt.expectedGlobal, tt.expectedEmails, tt.expectedContacts, stats)
+			}
+			if blocked := !stats.Global.BlockedUntil.IsZero(); blocked != (tt.expectedGlobal > 0) {
+				t.Errorf("expected global pause to be %v, got %v", tt.expectedGlobal > 0, stats.Global.BlockedUntil)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			err := limiter.Wait(ctx, unsend.ENDPOINT_GROUP_CONTACTS)
+			if tt.expectedGlobal > 0 && !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("expected contacts to wait for the global pause, got %v", err)
+			}
+			if tt.expectedGlobal == 0 && err != nil {
+				t.Errorf("expected contacts to be unaffected, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package unsend
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// ResponseMeta describes the HTTP response behind a call. It is filled for
+// failed calls too, as far as the call got.
+type ResponseMeta struct {
+	StatusCode int
+	Header     http.Header
+	// Body is the raw body of the last response.
+	Body []byte
+	// Latency covers the whole call, including retries and rate limiting.
+	Latency  time.Duration
+	Attempts int
+	// Local reports that the sandbox or idempotency cache answered the call
+	// without a request to the API. It is false for calls the client refused,
+	// such as an invalid request, which leave the rest of the meta empty.
+	Local bool
+}
+
+// RequestId returns the X-Request-Id response header, if the server sent one.
+func (m *ResponseMeta) RequestId() string {
+	return m.Header.Get("X-Request-Id")
+}
+
+type responseMetaContextKey struct{}
+
+// WithResponse returns a context that fills meta when passed to any method,
+// for reading headers and status codes the decoded result leaves out:
+//
+//	var meta unsend.ResponseMeta
+//	contact, err := client.Contacts.GetContact(unsend.WithResponse(ctx, &meta), request)
+//	remaining := meta.Header.Get("X-RateLimit-Remaining")
+func WithResponse(ctx context.Context, meta *ResponseMeta) context.Context {
+	return context.WithValue(ctx, responseMetaContextKey{}, meta)
+}
+
+func fillResponseMeta(ctx context.Context, exchange *exchange, latency time.Duration) {
+	meta, ok := ctx.Value(responseMetaContextKey{}).(*ResponseMeta)
+	if !ok || meta == nil {
+		return
+	}
+
+	*meta = ResponseMeta{Latency: latency}
+	if exchange == nil {
+		return
+	}
+	if exchange.local {
+		meta.Local = true
+		return
+	}
+
+	meta.Attempts = exchange.attempts
+	meta.Body = exchange.body
+	if exchange.response != nil {
+		meta.StatusCode = exchange.response.StatusCode
+		meta.Header = exchange.response.Header
+	}
+}
+
+// withoutResponse returns ctx without the meta from WithResponse, for calls the
+// SDK makes on behalf of another call, such as fetching domains to verify a
+// sender, so that only the caller's own call fills it.
+func withoutResponse(ctx context.Context) context.Context {
+	if ctx.Value(responseMetaContextKey{}) == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, responseMetaContextKey{}, (*ResponseMeta)(nil))
+}
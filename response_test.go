@@ -0,0 +1,129 @@
+package unsend_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+func TestWithResponse(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-123")
+		w.Header().Set("X-RateLimit-Remaining", "9")
+		if strings.HasPrefix(r.URL.Path, "/api/v1/domains") {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "not found"}`))
+			return
+		}
+		if attempts++; attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": "contact123", "email": "test@example.com"}`))
+	}))
+	defer server.Close()
+
+	client, err := unsend.NewClient(
+		unsend.WithApiKey("test-api-key"),
+		unsend.WithBaseUrl(server.URL),
+		unsend.WithConfigFile(filepath.Join(t.TempDir(), "missing.toml")),
+		unsend.WithRetryPolicy(&unsend.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var meta unsend.ResponseMeta
+	contact, err := client.Contacts.GetContact(unsend.WithResponse(context.Background(), &meta), unsend.GetContactRequest{ContactBookId: "book123", ContactId: "contact123"})
+	if err != nil || contact.Id != "contact123" {
+		t.Fatalf("expected contact, got %+v, %v", contact, err)
+	}
+
+	if meta.StatusCode != http.StatusCreated || meta.Attempts != 2 || meta.Local {
+		t.Errorf("expected status 201 after 2 attempts, got %+v", meta)
+	}
+	if meta.RequestId() != "req-123" || meta.Header.Get("X-RateLimit-Remaining") != "9" {
+		t.Errorf("expected response headers, got %v", meta.Header)
+	}
+	if !strings.Contains(string(meta.Body), `"contact123"`) || meta.Latency <= 0 {
+		t.Errorf("expected raw body and latency, got %s, %v", meta.Body, meta.Latency)
+	}
+
+	var failed unsend.ResponseMeta
+	_, err = client.Domains.GetDomain(unsend.WithResponse(context.Background(), &failed), unsend.GetDomainRequest{DomainId: 1})
+	var apiErr *unsend.APIError
+	if !errors.As(err, &apiErr) || failed.StatusCode != http.StatusNotFound || string(failed.Body) != `{"error": "not found"}` {
+		t.Errorf("expected metadata for a failed call, got %+v, %v", failed, err)
+	}
+}
+
+func TestWithResponseLocal(t *testing.T) {
+	client, err := unsend.NewClient(
+		unsend.WithApiKey("test-api-key"),
+		unsend.WithBaseUrl("http://unsend.invalid"),
+		unsend.WithConfigFile(filepath.Join(t.TempDir(), "missing.toml")),
+		unsend.WithDryRun(),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var meta unsend.ResponseMeta
+	_, err = client.Emails.SendEmail(unsend.WithResponse(context.Background(), &meta), unsend.SendEmailRequest{To: []string{"a@b.c"}, From: "hello@unsend.dev", Text: "Hi"})
+	if err != nil || !meta.Local || meta.StatusCode != 0 {
+		t.Errorf("expected a local response, got %+v, %v", meta, err)
+	}
+}
+
+func TestWithResponseOnlyFillsTheCallersCall(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id": 1, "name": "unsend.dev", "status": "SUCCESS"}]`))
+	}))
+	defer server.Close()
+
+	client, err := unsend.NewClient(
+		unsend.WithApiKey("test-api-key"),
+		unsend.WithBaseUrl(server.URL),
+		unsend.WithConfigFile(filepath.Join(t.TempDir(), "missing.toml")),
+		unsend.WithSenderVerification(time.Minute),
+		unsend.WithRecipientOverride(unsend.RecipientOverride{To: []string{"dev@unsend.dev"}}),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		name string
+		call func(ctx context.Context) error
+	}{
+		{"Unverified sender", func(ctx context.Context) error {
+			_, err := client.Emails.SendEmail(ctx, unsend.SendEmailRequest{To: []string{"a@b.c"}, From: "hello@unknown.dev", Text: "Hi"})
+			return err
+		}},
+		{"Refused by the client", func(ctx context.Context) error {
+			_, err := client.Campaigns.SendCampaign(ctx, unsend.SendCampaignRequest{CampaignId: "campaign123"})
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var meta unsend.ResponseMeta
+			if err := tt.call(unsend.WithResponse(context.Background(), &meta)); err == nil {
+				t.Fatalf("expected an error")
+			}
+			if meta.StatusCode != 0 || meta.Header != nil || meta.Body != nil || meta.Local {
+				t.Errorf("expected empty metadata for a call that was refused, got %+v", meta)
+			}
+		})
+	}
+}
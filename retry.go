@@ -0,0 +1,159 @@
+package unsend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const DEFAULT_RETRY_MAX_ATTEMPTS = 3
+const DEFAULT_RETRY_INITIAL_BACKOFF = 500 * time.Millisecond
+const DEFAULT_RETRY_MAX_BACKOFF = 10 * time.Second
+
+// APIError is returned when the API responds with a non-2xx status.
+type APIError struct {
+	StatusCode int
+	Body       string
+	Header     http.Header
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("received non-2xx response: %d - %s", e.StatusCode, e.Body)
+}
+
+// IsTemporary reports whether repeating the request that returned err might
+// succeed: 408, 429 and 5xx responses, and requests that failed in the
+// transport, such as a refused connection or a Client.Timeout. Everything
+// else is not temporary, including other 4xx responses, errors found before
+// the request was sent, such as a request that isn't valid, and a cancelled
+// context or one past its deadline.
+func IsTemporary(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusRequestTimeout || apiErr.StatusCode == http.StatusTooManyRequests ||
+			apiErr.StatusCode >= 500
+	}
+
+	// http.Client wraps transport failures in a url.Error, including its own
+	// timeout, which also matches context.DeadlineExceeded.
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return true
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// RetryPolicy retries network errors, 429 and 5xx responses with exponential
+// backoff. GET, HEAD, PUT and DELETE are always retried; POST and PATCH only
+// when the request carries an Idempotency-Key, so a retry can't send an email
+// or create a contact twice.
+type RetryPolicy struct {
+	// MaxAttempts includes the first attempt. Defaults to 3.
+	MaxAttempts int
+	// InitialBackoff doubles after each attempt up to MaxBackoff. A
+	// Retry-After from the server is honoured up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    DEFAULT_RETRY_MAX_ATTEMPTS,
+		InitialBackoff: DEFAULT_RETRY_INITIAL_BACKOFF,
+		MaxBackoff:     DEFAULT_RETRY_MAX_BACKOFF,
+	}
+}
+
+// attempts returns how many times req may be sent.
+func (p *RetryPolicy) attempts(req *http.Request) int {
+	if p == nil {
+		return 1
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		if req.Header.Get(IDEMPOTENCY_KEY_HEADER) == "" {
+			return 1
+		}
+	}
+
+	if p.MaxAttempts <= 0 {
+		return DEFAULT_RETRY_MAX_ATTEMPTS
+	}
+	return p.MaxAttempts
+}
+
+func (p *RetryPolicy) shouldRetry(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	return err != nil
+}
+
+// backoff returns how long to wait after the given attempt failed.
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	initial := p.InitialBackoff
+	if initial <= 0 {
+		initial = DEFAULT_RETRY_INITIAL_BACKOFF
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DEFAULT_RETRY_MAX_BACKOFF
+	}
+
+	delay := initial << (attempt - 1)
+	if delay <= 0 || delay > maxBackoff {
+		delay = maxBackoff
+	}
+	// Equal jitter keeps at least half the delay while spreading out clients
+	// that failed together.
+	delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+
+	if resp != nil {
+		now := time.Now()
+		if until := rateLimitPause(resp.Header, now); until.After(now) {
+			delay = max(delay, until.Sub(now))
+		}
+	}
+
+	return min(delay, maxBackoff)
+}
+
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
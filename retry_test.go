@@ -0,0 +1,48 @@
+package unsend_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+func TestIsTemporary(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"No error", nil, false},
+		{"Request timeout", &unsend.APIError{StatusCode: http.StatusRequestTimeout}, true},
+		{"Rate limited", &unsend.APIError{StatusCode: http.StatusTooManyRequests}, true},
+		{"Server error", fmt.Errorf("request failed: %w", &unsend.APIError{StatusCode: http.StatusBadGateway}), true},
+		{"Conflict", &unsend.APIError{StatusCode: http.StatusConflict}, false},
+		{"Not found", &unsend.APIError{StatusCode: http.StatusNotFound}, false},
+		{"Connection refused", fmt.Errorf("request failed: %w", &url.Error{Op: "Post", URL: "http://127.0.0.1", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}), true},
+		{"Client timeout", fmt.Errorf("request failed: %w", &url.Error{Op: "Post", URL: "http://127.0.0.1", Err: context.DeadlineExceeded}), true},
+		{"Truncated body", fmt.Errorf("failed to read response body: %w", io.ErrUnexpectedEOF), true},
+		{"Cancelled", fmt.Errorf("request failed: %w", &url.Error{Op: "Post", URL: "http://127.0.0.1", Err: context.Canceled}), false},
+		{"Deadline", fmt.Errorf("request failed: %w", context.DeadlineExceeded), false},
+		{"Invalid request", fmt.Errorf("[ERROR]: SendEmailRequest not valid; %w", &unsend.ValidationError{Errors: []string{"'To' is required"}}), false},
+		{"Sender not verified", &unsend.SenderDomainError{Domain: "example.com"}, false},
+		{"Unsupported", unsend.ErrUnsupportedByServer, false},
+		{"Key reused", fmt.Errorf("request failed: %w", unsend.ErrIdempotencyKeyReused), false},
+		{"Campaign restricted", fmt.Errorf("request failed: %w", unsend.ErrCampaignSendRestricted), false},
+		{"Unknown error", errors.New("something else"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := unsend.IsTemporary(tt.err); actual != tt.expected {
+				t.Errorf("expected IsTemporary(%v) to be %v, got %v", tt.err, tt.expected, actual)
+			}
+		})
+	}
+}
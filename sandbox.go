@@ -0,0 +1,195 @@
+package unsend
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const MODE_DRY_RUN = "dry-run"
+
+// SandboxOperation is a write the sandbox intercepted or changed.
+type SandboxOperation struct {
+	Method string
+	Path   string
+	// Body is the request body after any recipients were removed.
+	Body json.RawMessage
+	// Sent reports whether the request reached the API. SyntheticId is the id
+	// returned to the caller when it didn't.
+	Sent              bool
+	SyntheticId       string
+	RemovedRecipients []string
+	At                time.Time
+}
+
+// Sandbox keeps a client from making real changes. With DryRun every write is
+// validated as usual but answered locally with a synthetic id; reads still
+// reach the API. With AllowedRecipientDomains, emails are only sent to
+// recipients at those domains and emails with no allowed To address are not
+// sent at all.
+type Sandbox struct {
+	DryRun                  bool
+	AllowedRecipientDomains []string
+
+	mu         sync.Mutex
+	operations []SandboxOperation
+	nextId     int
+}
+
+// Operations returns the writes intercepted so far, oldest first.
+func (s *Sandbox) Operations() []SandboxOperation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]SandboxOperation(nil), s.operations...)
+}
+
+func (s *Sandbox) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.operations = nil
+}
+
+// intercept applies the sandbox to req before it is sent. It returns a
+// synthetic id when the request must not reach the API.
+func (s *Sandbox) intercept(c *Client, req *http.Request) (string, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return "", nil
+	}
+
+	op := SandboxOperation{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Body:   requestBody(req),
+		Sent:   !s.DryRun,
+		At:     time.Now(),
+	}
+
+	if len(s.AllowedRecipientDomains) > 0 && !s.DryRun && isCampaignSend(req) {
+		return "", ErrCampaignSendRestricted
+	}
+
+	if len(s.AllowedRecipientDomains) > 0 && isSendEmail(req) {
+		var email SendEmailRequest
+		if err := json.Unmarshal(op.Body, &email); err != nil {
+			return "", err
+		}
+
+		email.To, op.RemovedRecipients = s.filterRecipients(email.To, op.RemovedRecipients)
+		email.Cc, op.RemovedRecipients = s.filterRecipients(email.Cc, op.RemovedRecipients)
+		email.Bcc, op.RemovedRecipients = s.filterRecipients(email.Bcc, op.RemovedRecipients)
+
+		if len(op.RemovedRecipients) > 0 {
+			body, err := json.Marshal(email)
+			if err != nil {
+				return "", err
+			}
+			op.Body = body
+			setRequestBody(req, body)
+		}
+		if len(email.To) == 0 {
+			op.Sent = false
+		}
+	}
+
+	if op.Sent && len(op.RemovedRecipients) == 0 {
+		return "", nil
+	}
+
+	s.mu.Lock()
+	if !op.Sent {
+		s.nextId++
+		op.SyntheticId = "sandbox-" + strconv.Itoa(s.nextId)
+	}
+	s.operations = append(s.operations, op)
+	s.mu.Unlock()
+
+	if c.Logger != nil {
+		attrs := []slog.Attr{
+			slog.String("method", op.Method),
+			slog.String("path", op.Path),
+			slog.Bool("sent", op.Sent),
+			slog.Int("removed_recipients", len(op.RemovedRecipients)),
+		}
+		if c.Logger.Enabled(req.Context(), slog.LevelDebug) {
+			attrs = append(attrs, slog.String("request_body", c.redactBody(op.Body)))
+		}
+		c.Logger.LogAttrs(req.Context(), slog.LevelInfo, "unsend sandbox intercepted request", attrs...)
+	}
+
+	return op.SyntheticId, nil
+}
+
+func (s *Sandbox) filterRecipients(addresses []string, removed []string) ([]string, []string) {
+	var allowed []string
+	for _, address := range addresses {
+		if s.allowed(address) {
+			allowed = append(allowed, address)
+		} else {
+			removed = append(removed, address)
+		}
+	}
+	return allowed, removed
+}
+
+func (s *Sandbox) allowed(address string) bool {
+	_, domain, ok := strings.Cut(strings.TrimSuffix(strings.TrimSpace(address), ">"), "@")
+	if !ok {
+		return false
+	}
+
+	for _, allowed := range s.AllowedRecipientDomains {
+		if strings.EqualFold(domain, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSendEmail(req *http.Request) bool {
+	return req.Method == http.MethodPost && strings.HasSuffix(strings.TrimSuffix(req.URL.Path, "/"), "/v1/emails")
+}
+
+func setRequestBody(req *http.Request, body []byte) {
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	req.ContentLength = int64(len(body))
+}
+
+// syntheticResponse fills result for a request answered by the sandbox,
+// echoing the request body and setting the id fields the caller expects.
+func syntheticResponse(result interface{}, body []byte, id string) {
+	if len(body) > 0 {
+		json.Unmarshal(body, result)
+		// Request fields the response type lacks aren't unknown API fields.
+		if v := reflect.ValueOf(result).Elem(); v.Kind() == reflect.Struct {
+			if extra := v.FieldByName("Extra"); extra.IsValid() && extra.CanSet() {
+				extra.Set(reflect.Zero(extra.Type()))
+			}
+		}
+	}
+
+	switch r := result.(type) {
+	case *EmailIdResponse:
+		r.EmailId = id
+	case *ContactIdResponse:
+		r.ContactId = id
+	case *DeleteContactResponse:
+		r.Success = true
+	case *GetCampaignResponse:
+		r.Id = id
+		r.Status = CAMPAIGN_STATUS_DRAFT
+	case *CampaignActionResponse:
+		r.Success = true
+	case *VerifyDomainResponse:
+		r.Message = "dry run: domain verification not started"
+	}
+}
@@ -0,0 +1,152 @@
+package unsend_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+type recordedRequest struct {
+	method string
+	path   string
+	body   map[string]interface{}
+}
+
+func newSandboxTestServer(t *testing.T) (*httptest.Server, func() []recordedRequest) {
+	var mu sync.Mutex
+	var requests []recordedRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		decoded := map[string]interface{}{}
+		json.Unmarshal(body, &decoded)
+
+		mu.Lock()
+		requests = append(requests, recordedRequest{r.Method, r.URL.Path, decoded})
+		mu.Unlock()
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"emailId": "real-email", "id": "real-email", "contactId": "real-contact"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []recordedRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]recordedRequest(nil), requests...)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	server, requests := newSandboxTestServer(t)
+
+	client, err := unsend.NewClient(
+		unsend.WithApiKey("test-api-key"),
+		unsend.WithBaseUrl(server.URL),
+		unsend.WithConfigFile(filepath.Join(t.TempDir(), "missing.toml")),
+		unsend.WithDryRun(),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	ctx := context.Background()
+
+	sent, err := client.Emails.SendEmail(ctx, unsend.SendEmailRequest{To: []string{"a@b.c"}, From: "hello@unsend.dev", Text: "Hi"})
+	if err != nil || sent.EmailId != "sandbox-1" {
+		t.Fatalf("expected synthetic email id, got %+v, %v", sent, err)
+	}
+
+	contact, err := client.Contacts.CreateContact(ctx, unsend.CreateContactRequest{ContactBookId: "book123", Email: "test@example.com"})
+	if err != nil || contact.ContactId != "sandbox-2" {
+		t.Fatalf("expected synthetic contact id, got %+v, %v", contact, err)
+	}
+
+	deleted, err := client.Contacts.DeleteContact(ctx, unsend.DeleteContactRequest{ContactBookId: "book123", ContactId: "c1"})
+	if err != nil || !deleted.Success {
+		t.Fatalf("expected synthetic delete, got %+v, %v", deleted, err)
+	}
+
+	if _, err := client.Emails.SendEmail(ctx, unsend.SendEmailRequest{From: "hello@unsend.dev"}); err == nil {
+		t.Errorf("expected writes to still be validated")
+	}
+
+	email, err := client.Emails.GetEmail(ctx, unsend.GetEmailRequest{EmailId: "real-email"})
+	if err != nil || email.Id != "real-email" {
+		t.Fatalf("expected reads to reach the API, got %+v, %v", email, err)
+	}
+
+	if got := requests(); len(got) != 1 || got[0].method != http.MethodGet {
+		t.Errorf("expected only the read to reach the API, got %+v", got)
+	}
+
+	operations := client.Sandbox.Operations()
+	if len(operations) != 3 {
+		t.Fatalf("expected 3 recorded operations, got %d", len(operations))
+	}
+	if operations[0].Path != "/api/v1/emails" || operations[0].Sent || operations[0].SyntheticId != "sandbox-1" {
+		t.Errorf("unexpected first operation %+v", operations[0])
+	}
+}
+
+func TestRecipientAllowlist(t *testing.T) {
+	server, requests := newSandboxTestServer(t)
+
+	client, err := unsend.NewClient(
+		unsend.WithApiKey("test-api-key"),
+		unsend.WithBaseUrl(server.URL),
+		unsend.WithConfigFile(filepath.Join(t.TempDir(), "missing.toml")),
+		unsend.WithRecipientAllowlist("unsend.dev"),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	ctx := context.Background()
+
+	sent, err := client.Emails.SendEmail(ctx, unsend.SendEmailRequest{
+		To:   []string{"qa@unsend.dev", "customer@example.com"},
+		Cc:   []string{"Boss <boss@UNSEND.dev>"},
+		Bcc:  []string{"audit@example.com"},
+		From: "hello@unsend.dev",
+		Text: "Hi",
+	})
+	if err != nil || sent.EmailId != "real-email" {
+		t.Fatalf("expected email to be sent, got %+v, %v", sent, err)
+	}
+
+	got := requests()
+	if len(got) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(got))
+	}
+	if !reflect.DeepEqual(got[0].body["to"], []interface{}{"qa@unsend.dev"}) ||
+		!reflect.DeepEqual(got[0].body["cc"], []interface{}{"Boss <boss@UNSEND.dev>"}) ||
+		got[0].body["bcc"] != nil {
+		t.Errorf("expected recipients outside the allowlist to be removed, got %v", got[0].body)
+	}
+
+	suppressed, err := client.Emails.SendEmail(ctx, unsend.SendEmailRequest{To: []string{"customer@example.com"}, From: "hello@unsend.dev", Text: "Hi"})
+	if err != nil || suppressed.EmailId == "real-email" {
+		t.Fatalf("expected email with no allowed recipients not to be sent, got %+v, %v", suppressed, err)
+	}
+	if len(requests()) != 1 {
+		t.Errorf("expected no further requests, got %d", len(requests()))
+	}
+
+	operations := client.Sandbox.Operations()
+	expectedRemoved := [][]string{{"customer@example.com", "audit@example.com"}, {"customer@example.com"}}
+	for i, op := range operations {
+		if !reflect.DeepEqual(op.RemovedRecipients, expectedRemoved[i]) {
+			t.Errorf("expected removed recipients %v, got %v", expectedRemoved[i], op.RemovedRecipients)
+		}
+	}
+	if len(operations) != 2 || !operations[0].Sent || operations[1].Sent {
+		t.Errorf("unexpected operations %+v", operations)
+	}
+}
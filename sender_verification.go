@@ -0,0 +1,186 @@
+package unsend
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+)
+
+type SenderDomainError struct {
+	Domain     string
+	Registered bool
+	Status     string
+	DkimStatus string
+	SpfDetails string
+}
+
+func (e *SenderDomainError) Error() string {
+	if !e.Registered {
+		return fmt.Sprintf("[ERROR]: Sender domain '%s' is not registered on this account", e.Domain)
+	}
+	return fmt.Sprintf("[ERROR]: Sender domain '%s' is not verified; status: %s, dkim: %s, spf: %s", e.Domain, e.Status, e.DkimStatus, e.SpfDetails)
+}
+
+// SenderVerifier checks From addresses against the account's domains, caching
+// the GetDomains result for TTL. A domain that isn't verified causes one early
+// refresh, in case it was verified since, and is then refused from the cache
+// for TTL as well.
+type SenderVerifier struct {
+	Domains Domains
+	TTL     time.Duration
+
+	mu        sync.Mutex
+	domains   []GetDomainsResponse
+	fetchedAt time.Time
+	// fetching is closed when the GetDomains call in flight returns, and
+	// fetches counts the ones that succeeded.
+	fetching chan struct{}
+	fetches  int
+	// refused holds when each refused domain was last checked against a
+	// fresh list.
+	refused map[string]time.Time
+}
+
+func NewSenderVerifier(domains Domains, ttl time.Duration) *SenderVerifier {
+	return &SenderVerifier{
+		Domains: domains,
+		TTL:     ttl,
+	}
+}
+
+func (v *SenderVerifier) Verify(ctx context.Context, from string) error {
+	address, err := mail.ParseAddress(from)
+	if err != nil {
+		return fmt.Errorf("[ERROR]: 'From' is not a valid address; %w", err)
+	}
+	domain := strings.ToLower(address.Address[strings.LastIndex(address.Address, "@")+1:])
+
+	domains, refreshed, err := v.getDomains(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	verifyErr := checkSenderDomain(domains, domain)
+	if verifyErr != nil && !refreshed && !v.recentlyRefused(domain) {
+		// The cached list may predate a domain being added or verified.
+		domains, refreshed, err = v.getDomains(ctx, true)
+		if err != nil {
+			return err
+		}
+		verifyErr = checkSenderDomain(domains, domain)
+	}
+
+	if verifyErr != nil {
+		if refreshed {
+			v.refuse(domain)
+		}
+		return verifyErr
+	}
+	return nil
+}
+
+func (v *SenderVerifier) Invalidate() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.domains = nil
+	v.fetchedAt = time.Time{}
+	v.refused = nil
+}
+
+func (v *SenderVerifier) recentlyRefused(domain string) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	refusedAt, ok := v.refused[domain]
+	return ok && time.Since(refusedAt) < v.TTL
+}
+
+func (v *SenderVerifier) refuse(domain string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.refused == nil {
+		v.refused = map[string]time.Time{}
+	}
+	v.refused[domain] = time.Now()
+}
+
+// getDomains returns the cached domains, or fetches them when the cache has
+// expired or force is set. The lock isn't held during the fetch; concurrent
+// callers wait for the one in flight instead of starting their own.
+func (v *SenderVerifier) getDomains(ctx context.Context, force bool) ([]GetDomainsResponse, bool, error) {
+	v.mu.Lock()
+	for {
+		if !force && v.domains != nil && time.Since(v.fetchedAt) < v.TTL {
+			domains := v.domains
+			v.mu.Unlock()
+			return domains, false, nil
+		}
+		if v.fetching == nil {
+			break
+		}
+
+		fetching, fetches := v.fetching, v.fetches
+		v.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		case <-fetching:
+		}
+		v.mu.Lock()
+		if v.fetches != fetches && v.domains != nil {
+			domains := v.domains
+			v.mu.Unlock()
+			return domains, true, nil
+		}
+		// That fetch failed or was invalidated, so make our own.
+	}
+
+	fetching := make(chan struct{})
+	v.fetching = fetching
+	v.mu.Unlock()
+
+	response, err := v.Domains.GetDomains(withoutResponse(ctx))
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.fetching = nil
+	close(fetching)
+
+	if err != nil {
+		return nil, false, fmt.Errorf("[ERROR]: Failed to fetch domains for sender verification; %w", err)
+	}
+
+	v.domains = []GetDomainsResponse{}
+	if response != nil {
+		v.domains = *response
+	}
+	v.fetchedAt = time.Now()
+	v.fetches++
+
+	return v.domains, true, nil
+}
+
+func checkSenderDomain(domains []GetDomainsResponse, domain string) *SenderDomainError {
+	for _, d := range domains {
+		if !strings.EqualFold(d.Name, domain) {
+			continue
+		}
+		if d.Status == DOMAIN_STATUS_SUCCESS {
+			return nil
+		}
+		return &SenderDomainError{
+			Domain:     domain,
+			Registered: true,
+			Status:     d.Status,
+			DkimStatus: d.DkimStatus,
+			SpfDetails: d.SpfDetails,
+		}
+	}
+
+	return &SenderDomainError{Domain: domain}
+}
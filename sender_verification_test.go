@@ -0,0 +1,226 @@
+package unsend_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+func newSenderVerificationServer(t *testing.T, domainCalls *int32, emailCalls *int32) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/domains" && r.Method == http.MethodGet {
+			atomic.AddInt32(domainCalls, 1)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{"id": 1, "name": "verified.dev", "status": "SUCCESS", "dkimStatus": "SUCCESS", "spfDetails": "SUCCESS"},
+				{"id": 2, "name": "pending.dev", "status": "PENDING", "dkimStatus": "PENDING", "spfDetails": "NOT_STARTED"}
+			]`))
+		} else if r.URL.Path == "/api/v1/emails" && r.Method == http.MethodPost {
+			atomic.AddInt32(emailCalls, 1)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"emailId": "12345"}`))
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "not found"}`))
+		}
+	}))
+}
+
+func TestSenderVerifierVerify(t *testing.T) {
+	var domainCalls, emailCalls int32
+	server := newSenderVerificationServer(t, &domainCalls, &emailCalls)
+	defer server.Close()
+
+	client := &unsend.Client{
+		Client: &http.Client{},
+	}
+	client.BaseUrl, _ = url.Parse(server.URL)
+	client.Domains = &unsend.DomainsImpl{Client: client}
+
+	tests := []struct {
+		name          string
+		from          string
+		expectedError *unsend.SenderDomainError
+	}{
+		{
+			name:          "Verified domain",
+			from:          "Sender <hello@Verified.dev>",
+			expectedError: nil,
+		},
+		{
+			name: "Unverified domain",
+			from: "hello@pending.dev",
+			expectedError: &unsend.SenderDomainError{
+				Domain:     "pending.dev",
+				Registered: true,
+				Status:     "PENDING",
+				DkimStatus: "PENDING",
+				SpfDetails: "NOT_STARTED",
+			},
+		},
+		{
+			name:          "Unknown domain",
+			from:          "hello@unknown.dev",
+			expectedError: &unsend.SenderDomainError{Domain: "unknown.dev"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := unsend.NewSenderVerifier(client.Domains, time.Minute)
+			err := verifier.Verify(context.Background(), tt.from)
+
+			if tt.expectedError == nil {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+
+			var domainErr *unsend.SenderDomainError
+			if !errors.As(err, &domainErr) {
+				t.Fatalf("expected SenderDomainError, got %v", err)
+			}
+			if *domainErr != *tt.expectedError {
+				t.Errorf("expected error to be %+v, got %+v", tt.expectedError, domainErr)
+			}
+		})
+	}
+}
+
+func TestSenderVerifierCachesDomains(t *testing.T) {
+	var domainCalls, emailCalls int32
+	server := newSenderVerificationServer(t, &domainCalls, &emailCalls)
+	defer server.Close()
+
+	client := &unsend.Client{
+		Client: &http.Client{},
+	}
+	client.BaseUrl, _ = url.Parse(server.URL)
+	client.Domains = &unsend.DomainsImpl{Client: client}
+
+	verifier := unsend.NewSenderVerifier(client.Domains, time.Minute)
+	for i := 0; i < 3; i++ {
+		if err := verifier.Verify(context.Background(), "hello@verified.dev"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	if domainCalls != 1 {
+		t.Errorf("expected domains to be fetched once, got %d", domainCalls)
+	}
+
+	verifier.Invalidate()
+	if err := verifier.Verify(context.Background(), "hello@verified.dev"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if domainCalls != 2 {
+		t.Errorf("expected domains to be fetched again after Invalidate, got %d", domainCalls)
+	}
+}
+
+func TestSenderVerifierCachesRefusals(t *testing.T) {
+	var domainCalls, emailCalls int32
+	server := newSenderVerificationServer(t, &domainCalls, &emailCalls)
+	defer server.Close()
+
+	client := &unsend.Client{
+		Client: &http.Client{},
+	}
+	client.BaseUrl, _ = url.Parse(server.URL)
+	client.Domains = &unsend.DomainsImpl{Client: client}
+
+	verifier := unsend.NewSenderVerifier(client.Domains, time.Minute)
+	if err := verifier.Verify(context.Background(), "hello@verified.dev"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		var domainErr *unsend.SenderDomainError
+		if err := verifier.Verify(context.Background(), "hello@pending.dev"); !errors.As(err, &domainErr) {
+			t.Fatalf("expected SenderDomainError, got %v", err)
+		}
+	}
+
+	if domainCalls != 2 {
+		t.Errorf("expected one early refresh for the unverified domain, got %d fetches", domainCalls)
+	}
+}
+
+func TestSenderVerifierSharesFetches(t *testing.T) {
+	var domainCalls, emailCalls int32
+	server := newSenderVerificationServer(t, &domainCalls, &emailCalls)
+	defer server.Close()
+
+	client := &unsend.Client{
+		Client: &http.Client{},
+	}
+	client.BaseUrl, _ = url.Parse(server.URL)
+	client.Domains = &unsend.DomainsImpl{Client: client}
+
+	verifier := unsend.NewSenderVerifier(client.Domains, time.Minute)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := verifier.Verify(context.Background(), "hello@verified.dev"); err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls := atomic.LoadInt32(&domainCalls); calls != 1 {
+		t.Errorf("expected concurrent verifications to share one fetch, got %d", calls)
+	}
+}
+
+func TestSendEmailWithSenderVerification(t *testing.T) {
+	var domainCalls, emailCalls int32
+	server := newSenderVerificationServer(t, &domainCalls, &emailCalls)
+	defer server.Close()
+
+	client := &unsend.Client{
+		Client: &http.Client{},
+	}
+	client.BaseUrl, _ = url.Parse(server.URL)
+	client.Domains = &unsend.DomainsImpl{Client: client}
+	client.Emails = &unsend.EmailsImpl{Client: client}
+	client.SenderVerifier = unsend.NewSenderVerifier(client.Domains, time.Minute)
+
+	_, err := client.Emails.SendEmail(context.Background(), unsend.SendEmailRequest{
+		To:   []string{"a@b.c"},
+		From: "hello@pending.dev",
+	})
+
+	expectedErrMsg := "[ERROR]: Sender domain 'pending.dev' is not verified; status: PENDING, dkim: PENDING, spf: NOT_STARTED"
+	if err == nil || err.Error() != expectedErrMsg {
+		t.Fatalf("expected error %v, got %v", expectedErrMsg, err)
+	}
+	if emailCalls != 0 {
+		t.Errorf("expected email not to be sent, got %d calls", emailCalls)
+	}
+
+	response, err := client.Emails.SendEmail(context.Background(), unsend.SendEmailRequest{
+		To:   []string{"a@b.c"},
+		From: "hello@verified.dev",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if response.EmailId != "12345" {
+		t.Errorf("expected email ID to be 12345, got %s", response.EmailId)
+	}
+}
@@ -0,0 +1,566 @@
+// Code generated by openapigen from openapi/unsend.json. DO NOT EDIT.
+
+package unsend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// apiOperations lists the operations of the API by the SDK method that
+// calls them.
+var apiOperations = []apiOperation{
+	{Method: http.MethodGet, Path: "/v1/emails/{emailId}", Name: "GetEmail"},
+	{Method: http.MethodPost, Path: "/v1/emails", Name: "SendEmail"},
+	{Method: http.MethodPatch, Path: "/v1/emails/{emailId}", Name: "UpdateSchedule"},
+	{Method: http.MethodPost, Path: "/v1/emails/{emailId}/cancel", Name: "CancelSchedule"},
+	{Method: http.MethodGet, Path: "/v1/contactBooks/{contactBookId}/contacts/{contactId}", Name: "GetContact"},
+	{Method: http.MethodPost, Path: "/v1/contactBooks/{contactBookId}/contacts", Name: "CreateContact"},
+	{Method: http.MethodPut, Path: "/v1/contactBooks/{contactBookId}/contacts/{contactId}", Name: "UpsertContact"},
+	{Method: http.MethodPatch, Path: "/v1/contactBooks/{contactBookId}/contacts/{contactId}", Name: "UpdateContact"},
+	{Method: http.MethodDelete, Path: "/v1/contactBooks/{contactBookId}/contacts/{contactId}", Name: "DeleteContact"},
+	{Method: http.MethodGet, Path: "/v1/domains", Name: "GetDomains"},
+	{Method: http.MethodGet, Path: "/v1/domains/{id}", Name: "GetDomain"},
+	{Method: http.MethodPost, Path: "/v1/domains", Name: "CreateDomain"},
+	{Method: http.MethodPut, Path: "/v1/domains/{id}/verify", Name: "VerifyDomain"},
+	{Method: http.MethodDelete, Path: "/v1/domains/{id}", Name: "DeleteDomain"},
+	{Method: http.MethodPost, Path: "/v1/campaigns", Name: "CreateCampaign"},
+	{Method: http.MethodGet, Path: "/v1/campaigns/{campaignId}", Name: "GetCampaign"},
+	{Method: http.MethodGet, Path: "/v1/campaigns", Name: "ListCampaigns"},
+	{Method: http.MethodPatch, Path: "/v1/campaigns/{campaignId}", Name: "UpdateCampaign"},
+	{Method: http.MethodPost, Path: "/v1/campaigns/{campaignId}/schedule", Name: "ScheduleCampaign"},
+	{Method: http.MethodPost, Path: "/v1/campaigns/{campaignId}/send", Name: "SendCampaign"},
+	{Method: http.MethodPost, Path: "/v1/campaigns/{campaignId}/pause", Name: "PauseCampaign"},
+	{Method: http.MethodPost, Path: "/v1/campaigns/{campaignId}/resume", Name: "ResumeCampaign"},
+	{Method: http.MethodDelete, Path: "/v1/campaigns/{campaignId}", Name: "DeleteCampaign"},
+}
+
+func (e *EmailsImpl) GetEmail(ctx context.Context, request GetEmailRequest) (*GetEmailResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("[ERROR]: GetEmailRequest not valid; %w", err)
+	}
+
+	path := "api/v1/emails/" + request.EmailId
+
+	req, err := e.Client.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, errors.New("[ERROR]: Failed to create Email.Get request")
+	}
+
+	response := new(GetEmailResponse)
+	err = e.Client.Execute(req, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (e *EmailsImpl) SendEmail(ctx context.Context, request SendEmailRequest) (*EmailIdResponse, error) {
+	if request.From == "" {
+		request.From = e.Client.DefaultFrom
+	}
+
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("[ERROR]: SendEmailRequest not valid; %w", err)
+	}
+
+	if err := request.prepare(ctx, e.Client); err != nil {
+		return nil, err
+	}
+
+	path := "api/v1/emails"
+
+	req, err := e.Client.NewRequestWithContext(ctx, http.MethodPost, path, request)
+	if err != nil {
+		return nil, errors.New("[ERROR]: Failed to create Send Email request")
+	}
+
+	response := new(EmailIdResponse)
+	err = e.Client.Execute(req, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (e *EmailsImpl) UpdateSchedule(ctx context.Context, request UpdateScheduleRequest) (*EmailIdResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("[ERROR]: UpdateScheduleRequest not valid; %w", err)
+	}
+
+	path := "api/v1/emails/" + request.EmailId
+
+	req, err := e.Client.NewRequestWithContext(ctx, http.MethodPatch, path, request)
+	if err != nil {
+		return nil, errors.New("[ERROR]: Failed to create Update Schedule request")
+	}
+
+	response := new(EmailIdResponse)
+	err = e.Client.Execute(req, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (e *EmailsImpl) CancelSchedule(ctx context.Context, request CancelScheduleRequest) (*EmailIdResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("[ERROR]: CancelScheduleRequest not valid; %w", err)
+	}
+
+	path := "api/v1/emails/" + request.EmailId + "/cancel"
+
+	req, err := e.Client.NewRequestWithContext(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return nil, errors.New("[ERROR]: Failed to create Update Schedule request")
+	}
+
+	response := new(EmailIdResponse)
+	err = e.Client.Execute(req, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *ContactsImpl) GetContact(ctx context.Context, request GetContactRequest) (*GetContactResponse, error) {
+	if request.ContactBookId == "" {
+		request.ContactBookId = c.Client.DefaultContactBookId
+	}
+
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("[ERROR]: GetContactRequest not valid; %w", err)
+	}
+
+	path := "api/v1/contactBooks/" + request.ContactBookId + "/contacts/" + request.ContactId
+
+	req, err := c.Client.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return &GetContactResponse{}, errors.New("[ERROR]: Failed to create Contact.Get request")
+	}
+
+	response := new(GetContactResponse)
+	err = c.Client.Execute(req, response)
+	if err != nil {
+		return &GetContactResponse{}, err
+	}
+
+	return response, nil
+}
+
+func (c *ContactsImpl) CreateContact(ctx context.Context, request CreateContactRequest) (*ContactIdResponse, error) {
+	if request.ContactBookId == "" {
+		request.ContactBookId = c.Client.DefaultContactBookId
+	}
+
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("[ERROR]: CreateContactRequest not valid; %w", err)
+	}
+
+	path := "api/v1/contactBooks/" + request.ContactBookId + "/contacts/"
+
+	req, err := c.Client.NewRequestWithContext(ctx, http.MethodPost, path, request)
+	if err != nil {
+		return &ContactIdResponse{}, errors.New("[ERROR]: Failed to create Contact.Create request")
+	}
+
+	response := new(ContactIdResponse)
+	err = c.Client.Execute(req, response)
+	if err != nil {
+		return &ContactIdResponse{}, err
+	}
+
+	return response, nil
+}
+
+func (c *ContactsImpl) UpsertContact(ctx context.Context, request UpsertContactRequest) (*ContactIdResponse, error) {
+	if request.ContactBookId == "" {
+		request.ContactBookId = c.Client.DefaultContactBookId
+	}
+
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("[ERROR]: UpsertContactRequest not valid; %w", err)
+	}
+
+	path := "api/v1/contactBooks/" + request.ContactBookId + "/contacts/" + request.ContactId
+
+	req, err := c.Client.NewRequestWithContext(ctx, http.MethodPut, path, request)
+	if err != nil {
+		return &ContactIdResponse{}, errors.New("[ERROR]: Failed to create Contact.Upsert request")
+	}
+
+	response := new(ContactIdResponse)
+	err = c.Client.Execute(req, response)
+	if err != nil {
+		return &ContactIdResponse{}, err
+	}
+
+	return response, nil
+}
+
+func (c *ContactsImpl) UpdateContact(ctx context.Context, request UpdateContactRequest) (*ContactIdResponse, error) {
+	if request.ContactBookId == "" {
+		request.ContactBookId = c.Client.DefaultContactBookId
+	}
+
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("[ERROR]: UpdateContactRequest not valid; %w", err)
+	}
+
+	path := "api/v1/contactBooks/" + request.ContactBookId + "/contacts/" + request.ContactId
+
+	req, err := c.Client.NewRequestWithContext(ctx, http.MethodPatch, path, request)
+	if err != nil {
+		return &ContactIdResponse{}, errors.New("[ERROR]: Failed to create Contact.Update request")
+	}
+
+	response := new(ContactIdResponse)
+	err = c.Client.Execute(req, response)
+	if err != nil {
+		return &ContactIdResponse{}, err
+	}
+
+	return response, nil
+}
+
+func (c *ContactsImpl) DeleteContact(ctx context.Context, request DeleteContactRequest) (*DeleteContactResponse, error) {
+	if request.ContactBookId == "" {
+		request.ContactBookId = c.Client.DefaultContactBookId
+	}
+
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("[ERROR]: DeleteContactRequest not valid; %w", err)
+	}
+
+	path := "api/v1/contactBooks/" + request.ContactBookId + "/contacts/" + request.ContactId
+
+	req, err := c.Client.NewRequestWithContext(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return &DeleteContactResponse{}, errors.New("[ERROR]: Failed to create Contact.Delete request")
+	}
+
+	response := new(DeleteContactResponse)
+	err = c.Client.Execute(req, response)
+	if err != nil {
+		return &DeleteContactResponse{}, err
+	}
+
+	return response, nil
+}
+
+func (d *DomainsImpl) GetDomains(ctx context.Context) (*[]GetDomainsResponse, error) {
+	path := "api/v1/domains"
+
+	req, err := d.Client.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response := new([]GetDomainsResponse)
+	err = d.Client.Execute(req, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (d *DomainsImpl) GetDomain(ctx context.Context, request GetDomainRequest) (*GetDomainsResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("[ERROR]: GetDomainRequest not valid; %w", err)
+	}
+
+	path := "api/v1/domains/" + strconv.Itoa(request.DomainId)
+
+	req, err := d.Client.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, errors.New("[ERROR]: Failed to create Domain.Get request")
+	}
+
+	response := new(GetDomainsResponse)
+	err = d.Client.Execute(req, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (d *DomainsImpl) CreateDomain(ctx context.Context, request CreateDomainRequest) (*GetDomainsResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("[ERROR]: CreateDomainRequest not valid; %w", err)
+	}
+
+	path := "api/v1/domains"
+
+	req, err := d.Client.NewRequestWithContext(ctx, http.MethodPost, path, request)
+	if err != nil {
+		return nil, errors.New("[ERROR]: Failed to create Domain.Create request")
+	}
+
+	response := new(GetDomainsResponse)
+	err = d.Client.Execute(req, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (d *DomainsImpl) VerifyDomain(ctx context.Context, request VerifyDomainRequest) (*VerifyDomainResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("[ERROR]: VerifyDomainRequest not valid; %w", err)
+	}
+
+	path := "api/v1/domains/" + strconv.Itoa(request.DomainId) + "/verify"
+
+	req, err := d.Client.NewRequestWithContext(ctx, http.MethodPut, path, nil)
+	if err != nil {
+		return nil, errors.New("[ERROR]: Failed to create Domain.Verify request")
+	}
+
+	response := new(VerifyDomainResponse)
+	err = d.Client.Execute(req, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (d *DomainsImpl) DeleteDomain(ctx context.Context, request DeleteDomainRequest) (*GetDomainsResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("[ERROR]: DeleteDomainRequest not valid; %w", err)
+	}
+
+	path := "api/v1/domains/" + strconv.Itoa(request.DomainId)
+
+	req, err := d.Client.NewRequestWithContext(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return nil, errors.New("[ERROR]: Failed to create Domain.Delete request")
+	}
+
+	response := new(GetDomainsResponse)
+	err = d.Client.Execute(req, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *CampaignsImpl) CreateCampaign(ctx context.Context, request CreateCampaignRequest) (*GetCampaignResponse, error) {
+	if request.From == "" {
+		request.From = c.Client.DefaultFrom
+	}
+
+	if request.ContactBookId == "" {
+		request.ContactBookId = c.Client.DefaultContactBookId
+	}
+
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("[ERROR]: CreateCampaignRequest not valid; %w", err)
+	}
+
+	if err := request.prepare(ctx, c.Client); err != nil {
+		return nil, err
+	}
+
+	path := "api/v1/campaigns"
+
+	req, err := c.Client.NewRequestWithContext(ctx, http.MethodPost, path, request)
+	if err != nil {
+		return nil, errors.New("[ERROR]: Failed to create CreateCampaign request")
+	}
+
+	response := new(GetCampaignResponse)
+	err = c.Client.Execute(req, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *CampaignsImpl) GetCampaign(ctx context.Context, request GetCampaignRequest) (*GetCampaignResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("[ERROR]: GetCampaignRequest not valid; %w", err)
+	}
+
+	path := "api/v1/campaigns/" + request.CampaignId
+
+	req, err := c.Client.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, errors.New("[ERROR]: Failed to create GetCampaign request")
+	}
+
+	response := new(GetCampaignResponse)
+	err = c.Client.Execute(req, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *CampaignsImpl) ListCampaigns(ctx context.Context, request ListCampaignsRequest) (*ListCampaignsResponse, error) {
+	path := "api/v1/campaigns"
+
+	query := url.Values{}
+	if request.Page != 0 {
+		query.Set("page", strconv.Itoa(request.Page))
+	}
+	if request.Status != "" {
+		query.Set("status", string(request.Status))
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	req, err := c.Client.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, errors.New("[ERROR]: Failed to create ListCampaigns request")
+	}
+
+	response := new(ListCampaignsResponse)
+	err = c.Client.Execute(req, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *CampaignsImpl) UpdateCampaign(ctx context.Context, request UpdateCampaignRequest) (*GetCampaignResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("[ERROR]: UpdateCampaignRequest not valid; %w", err)
+	}
+
+	path := "api/v1/campaigns/" + request.CampaignId
+
+	req, err := c.Client.NewRequestWithContext(ctx, http.MethodPatch, path, request)
+	if err != nil {
+		return nil, errors.New("[ERROR]: Failed to create UpdateCampaign request")
+	}
+
+	response := new(GetCampaignResponse)
+	err = c.Client.Execute(req, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *CampaignsImpl) ScheduleCampaign(ctx context.Context, request ScheduleCampaignRequest) (*CampaignActionResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("[ERROR]: ScheduleCampaignRequest not valid; %w", err)
+	}
+
+	path := "api/v1/campaigns/" + request.CampaignId + "/schedule"
+
+	req, err := c.Client.NewRequestWithContext(ctx, http.MethodPost, path, request)
+	if err != nil {
+		return nil, errors.New("[ERROR]: Failed to create ScheduleCampaign request")
+	}
+
+	response := new(CampaignActionResponse)
+	err = c.Client.Execute(req, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *CampaignsImpl) SendCampaign(ctx context.Context, request SendCampaignRequest) (*CampaignActionResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("[ERROR]: SendCampaignRequest not valid; %w", err)
+	}
+
+	path := "api/v1/campaigns/" + request.CampaignId + "/send"
+
+	req, err := c.Client.NewRequestWithContext(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return nil, errors.New("[ERROR]: Failed to create SendCampaign request")
+	}
+
+	response := new(CampaignActionResponse)
+	err = c.Client.Execute(req, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *CampaignsImpl) PauseCampaign(ctx context.Context, request PauseCampaignRequest) (*CampaignActionResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("[ERROR]: PauseCampaignRequest not valid; %w", err)
+	}
+
+	path := "api/v1/campaigns/" + request.CampaignId + "/pause"
+
+	req, err := c.Client.NewRequestWithContext(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return nil, errors.New("[ERROR]: Failed to create PauseCampaign request")
+	}
+
+	response := new(CampaignActionResponse)
+	err = c.Client.Execute(req, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *CampaignsImpl) ResumeCampaign(ctx context.Context, request ResumeCampaignRequest) (*CampaignActionResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("[ERROR]: ResumeCampaignRequest not valid; %w", err)
+	}
+
+	path := "api/v1/campaigns/" + request.CampaignId + "/resume"
+
+	req, err := c.Client.NewRequestWithContext(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return nil, errors.New("[ERROR]: Failed to create ResumeCampaign request")
+	}
+
+	response := new(CampaignActionResponse)
+	err = c.Client.Execute(req, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *CampaignsImpl) DeleteCampaign(ctx context.Context, request DeleteCampaignRequest) (*CampaignActionResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("[ERROR]: DeleteCampaignRequest not valid; %w", err)
+	}
+
+	path := "api/v1/campaigns/" + request.CampaignId
+
+	req, err := c.Client.NewRequestWithContext(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return nil, errors.New("[ERROR]: Failed to create DeleteCampaign request")
+	}
+
+	response := new(CampaignActionResponse)
+	err = c.Client.Execute(req, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,197 @@
+// Package tracecontext is a dependency-free unsend.Tracer that follows the W3C
+// Trace Context format. It continues traces from incoming traceparent headers,
+// propagates them to the Unsend API and hands finished spans to an exporter.
+package tracecontext
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+const TRACEPARENT_HEADER = "traceparent"
+const TRACESTATE_HEADER = "tracestate"
+
+// SpanContext identifies a span within a trace.
+type SpanContext struct {
+	TraceId    [16]byte
+	SpanId     [8]byte
+	Sampled    bool
+	TraceState string
+}
+
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceId != [16]byte{} && sc.SpanId != [8]byte{}
+}
+
+// Traceparent formats the span context as a traceparent header value.
+func (sc SpanContext) Traceparent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return "00-" + hex.EncodeToString(sc.TraceId[:]) + "-" + hex.EncodeToString(sc.SpanId[:]) + "-" + flags
+}
+
+// ParseTraceparent reads a traceparent header value.
+func ParseTraceparent(value string) (SpanContext, error) {
+	invalid := fmt.Errorf("invalid traceparent %q", value)
+
+	// Later versions may append fields; version 00 has exactly four.
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return SpanContext{}, invalid
+	}
+	if len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return SpanContext{}, invalid
+	}
+
+	var sc SpanContext
+	var flags [1]byte
+	if _, err := hex.Decode(sc.TraceId[:], []byte(parts[1])); err != nil {
+		return SpanContext{}, invalid
+	}
+	if _, err := hex.Decode(sc.SpanId[:], []byte(parts[2])); err != nil {
+		return SpanContext{}, invalid
+	}
+	if _, err := hex.Decode(flags[:], []byte(parts[3])); err != nil || !sc.IsValid() {
+		return SpanContext{}, invalid
+	}
+
+	sc.Sampled = flags[0]&1 == 1
+	return sc, nil
+}
+
+type spanContextKey struct{}
+
+// ContextWithSpanContext returns a context whose spans are children of sc.
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(spanContextKey{}).(SpanContext)
+	return sc, ok && sc.IsValid()
+}
+
+// Extract continues the trace in an incoming request's headers. Invalid or
+// missing headers leave ctx unchanged.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	sc, err := ParseTraceparent(header.Get(TRACEPARENT_HEADER))
+	if err != nil {
+		return ctx
+	}
+	sc.TraceState = header.Get(TRACESTATE_HEADER)
+	return ContextWithSpanContext(ctx, sc)
+}
+
+// Middleware calls Extract for every request, so SDK calls made while handling
+// it are children of the caller's span.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(Extract(r.Context(), r.Header)))
+	})
+}
+
+// SpanData is a finished span.
+type SpanData struct {
+	Name         string
+	SpanContext  SpanContext
+	ParentSpanId [8]byte
+	Start        time.Time
+	End          time.Time
+	Attributes   map[string]interface{}
+	Err          error
+}
+
+// Tracer implements unsend.Tracer. Export is called with every finished span.
+type Tracer struct {
+	Export func(span SpanData)
+}
+
+func (t *Tracer) Start(ctx context.Context, name string, attributes ...unsend.Attribute) (context.Context, unsend.Span) {
+	parent, hasParent := SpanContextFromContext(ctx)
+
+	sc := SpanContext{Sampled: true}
+	if hasParent {
+		sc.TraceId = parent.TraceId
+		sc.Sampled = parent.Sampled
+		sc.TraceState = parent.TraceState
+	} else {
+		rand.Read(sc.TraceId[:])
+	}
+	rand.Read(sc.SpanId[:])
+
+	s := &span{
+		tracer: t,
+		data: SpanData{
+			Name:        name,
+			SpanContext: sc,
+			Start:       time.Now(),
+			Attributes:  map[string]interface{}{},
+		},
+	}
+	if hasParent {
+		s.data.ParentSpanId = parent.SpanId
+	}
+	s.SetAttributes(attributes...)
+
+	return ContextWithSpanContext(ctx, sc), s
+}
+
+func (t *Tracer) Inject(ctx context.Context, header http.Header) {
+	sc, ok := SpanContextFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	header.Set(TRACEPARENT_HEADER, sc.Traceparent())
+	if sc.TraceState != "" {
+		header.Set(TRACESTATE_HEADER, sc.TraceState)
+	}
+}
+
+type span struct {
+	tracer *Tracer
+
+	mu    sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+func (s *span) SetAttributes(attributes ...unsend.Attribute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, attribute := range attributes {
+		s.data.Attributes[attribute.Key] = attribute.Value
+	}
+}
+
+func (s *span) RecordError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Err = err
+}
+
+func (s *span) End() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	data := s.data
+	s.mu.Unlock()
+
+	if s.tracer.Export != nil && data.SpanContext.Sampled {
+		s.tracer.Export(data)
+	}
+}
@@ -0,0 +1,121 @@
+package tracecontext_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/QGeeDev/unsend-go"
+	"github.com/QGeeDev/unsend-go/tracecontext"
+)
+
+const incomingTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestTracerContinuesIncomingTrace(t *testing.T) {
+	var received string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get(tracecontext.TRACEPARENT_HEADER)
+		if strings.HasSuffix(r.URL.Path, "/contacts/") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"emailId": "email123"}`))
+	}))
+	defer api.Close()
+
+	var mu sync.Mutex
+	var spans []tracecontext.SpanData
+	tracer := &tracecontext.Tracer{Export: func(span tracecontext.SpanData) {
+		mu.Lock()
+		defer mu.Unlock()
+		spans = append(spans, span)
+	}}
+
+	client, err := unsend.NewClient(
+		unsend.WithApiKey("test-api-key"),
+		unsend.WithBaseUrl(api.URL),
+		unsend.WithConfigFile(filepath.Join(t.TempDir(), "missing.toml")),
+		unsend.WithTracer(tracer),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	handler := tracecontext.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client.Emails.SendEmail(r.Context(), unsend.SendEmailRequest{To: []string{"a@b.c"}, From: "hello@unsend.dev", Text: "Hi"})
+		client.Contacts.CreateContact(r.Context(), unsend.CreateContactRequest{ContactBookId: "book123", Email: "a@b.c"})
+	}))
+	request := httptest.NewRequest(http.MethodPost, "/signup", nil)
+	request.Header.Set(tracecontext.TRACEPARENT_HEADER, incomingTraceparent)
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	parent, _ := tracecontext.ParseTraceparent(incomingTraceparent)
+	send := spans[0]
+	if send.Name != "unsend.SendEmail" || send.SpanContext.TraceId != parent.TraceId || send.ParentSpanId != parent.SpanId {
+		t.Errorf("expected SendEmail to be a child of the incoming span, got %+v", send)
+	}
+	expected := map[string]interface{}{
+		unsend.ATTRIBUTE_OPERATION:        "SendEmail",
+		unsend.ATTRIBUTE_SERVICE:          "emails",
+		unsend.ATTRIBUTE_HTTP_METHOD:      http.MethodPost,
+		unsend.ATTRIBUTE_HTTP_STATUS_CODE: http.StatusOK,
+		unsend.ATTRIBUTE_RETRY_COUNT:      0,
+		unsend.ATTRIBUTE_EMAIL_ID:         "email123",
+	}
+	for key, value := range expected {
+		if send.Attributes[key] != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, send.Attributes[key])
+		}
+	}
+
+	create := spans[1]
+	if create.Name != "unsend.CreateContact" || create.Err == nil || create.Attributes[unsend.ATTRIBUTE_HTTP_STATUS_CODE] != http.StatusBadRequest {
+		t.Errorf("expected failed CreateContact span, got %+v", create)
+	}
+	if received != create.SpanContext.Traceparent() {
+		t.Errorf("expected the API to receive the span's traceparent, got %q", received)
+	}
+}
+
+func TestTracerStartsNewTrace(t *testing.T) {
+	tracer := &tracecontext.Tracer{}
+	ctx, span := tracer.Start(context.Background(), "root")
+	defer span.End()
+
+	header := http.Header{}
+	tracer.Inject(ctx, header)
+
+	sc, err := tracecontext.ParseTraceparent(header.Get(tracecontext.TRACEPARENT_HEADER))
+	if err != nil || !sc.Sampled {
+		t.Errorf("expected a sampled traceparent, got %q, %v", header.Get(tracecontext.TRACEPARENT_HEADER), err)
+	}
+}
+
+func TestParseTraceparent(t *testing.T) {
+	tests := []struct {
+		value string
+		valid bool
+	}{
+		{incomingTraceparent, true},
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-extra", true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", false},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", false},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if _, err := tracecontext.ParseTraceparent(tt.value); (err == nil) != tt.valid {
+			t.Errorf("expected %q valid=%v, got %v", tt.value, tt.valid, err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package unsend
+
+import (
+	"context"
+	"net/http"
+)
+
+const ATTRIBUTE_OPERATION = "unsend.operation"
+const ATTRIBUTE_SERVICE = "unsend.service"
+const ATTRIBUTE_EMAIL_ID = "unsend.email_id"
+const ATTRIBUTE_CONTACT_ID = "unsend.contact_id"
+const ATTRIBUTE_RETRY_COUNT = "unsend.retry_count"
+const ATTRIBUTE_HTTP_METHOD = "http.request.method"
+const ATTRIBUTE_HTTP_STATUS_CODE = "http.response.status_code"
+
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Tracer starts a span for each API call. Its shape follows OpenTelemetry:
+// the otelunsend module wraps an OpenTelemetry tracer and propagator, and
+// package tracecontext has a dependency-free implementation.
+type Tracer interface {
+	// Start returns a span that is a child of any span in ctx, and a context
+	// holding the new span.
+	Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, Span)
+	// Inject adds the trace headers for the span in ctx, such as traceparent,
+	// to an outgoing request.
+	Inject(ctx context.Context, header http.Header)
+}
+
+type Span interface {
+	SetAttributes(attributes ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// NoopTracer is the default Tracer. It records nothing.
+type NoopTracer struct{}
+
+func (NoopTracer) Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+func (NoopTracer) Inject(ctx context.Context, header http.Header) {}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(attributes ...Attribute) {}
+func (noopSpan) RecordError(err error)                 {}
+func (noopSpan) End()                                  {}
+
+// startSpan starts the span for an API call named after the SDK method, such
+// as "unsend.SendEmail", and propagates it on req.
+func (c *Client) startSpan(req *http.Request) (*http.Request, Span) {
+	if c.Tracer == nil {
+		return req, noopSpan{}
+	}
+
+	operation := endpointOperation(req.Method, req.URL.Path)
+	ctx, span := c.Tracer.Start(req.Context(), "unsend."+operation,
+		Attribute{ATTRIBUTE_OPERATION, operation},
+		Attribute{ATTRIBUTE_SERVICE, endpointGroup(req.URL.Path)},
+		Attribute{ATTRIBUTE_HTTP_METHOD, req.Method},
+	)
+
+	req = req.WithContext(ctx)
+	c.Tracer.Inject(ctx, req.Header)
+	return req, span
+}
+
+func (c *Client) endSpan(span Span, exchange *exchange, result interface{}, err error) {
+	var attributes []Attribute
+
+	if exchange != nil && !exchange.local {
+		attributes = append(attributes, Attribute{ATTRIBUTE_RETRY_COUNT, max(exchange.attempts-1, 0)})
+		if exchange.response != nil {
+			attributes = append(attributes, Attribute{ATTRIBUTE_HTTP_STATUS_CODE, exchange.response.StatusCode})
+		}
+	}
+
+	if err == nil {
+		switch r := result.(type) {
+		case *EmailIdResponse:
+			attributes = append(attributes, Attribute{ATTRIBUTE_EMAIL_ID, r.EmailId})
+		case *GetEmailResponse:
+			attributes = append(attributes, Attribute{ATTRIBUTE_EMAIL_ID, r.Id})
+		case *ContactIdResponse:
+			attributes = append(attributes, Attribute{ATTRIBUTE_CONTACT_ID, r.ContactId})
+		}
+	} else {
+		span.RecordError(err)
+	}
+
+	if len(attributes) > 0 {
+		span.SetAttributes(attributes...)
+	}
+	span.End()
+}
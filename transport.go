@@ -6,13 +6,31 @@ import (
 )
 
 type UnsendTransport struct {
-	ApiKey string
+	ApiKey      string
+	Credentials CredentialsProvider
+	// Base sends the authenticated request. Defaults to http.DefaultTransport.
+	Base http.RoundTripper
 }
 
 func (t *UnsendTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	req.Header.Add("user-agent", fmt.Sprintf("%s/%s", PACKAGE_NAME, VERSION))
-	req.Header.Add("version", VERSION)
-	req.Header.Add("content-type", "application/json")
-	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", t.ApiKey))
-	return http.DefaultTransport.RoundTrip(req)
+	apiKey := t.ApiKey
+	if t.Credentials != nil {
+		var err error
+		apiKey, err = t.Credentials.ApiKey(req.Context())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("user-agent", fmt.Sprintf("%s/%s", PACKAGE_NAME, VERSION))
+	req.Header.Set("version", VERSION)
+	req.Header.Set("content-type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
 }
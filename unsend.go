@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
@@ -14,46 +15,177 @@ import (
 )
 
 type Client struct {
-	Client   *http.Client
-	ApiKey   string
-	BaseUrl  *url.URL
-	Contacts Contacts
-	Domains  Domains
-	Emails   Emails
+	Client         *http.Client
+	ApiKey         string
+	BaseUrl        *url.URL
+	Campaigns      Campaigns
+	Contacts       Contacts
+	Domains        Domains
+	Emails         Emails
+	SenderVerifier *SenderVerifier
+	Credentials    CredentialsProvider
+	Logger         *slog.Logger
+	RedactFields   []string
+	RateLimiter    *RateLimiter
+	RetryPolicy    *RetryPolicy
+
+	// AutoIdempotencyKeys adds an Idempotency-Key to email and contact writes
+	// that don't already have one. IdempotencyCache, when set, replays the
+	// first successful response for a repeated key.
+	AutoIdempotencyKeys bool
+	IdempotencyCache    *IdempotencyCache
+
+	// RecipientOverride, when set, redirects every email to fixed addresses.
+	RecipientOverride *RecipientOverride
+
+	// Metrics, when set, is told about every API call, and Tracer starts a
+	// span for each one.
+	Metrics Metrics
+	Tracer  Tracer
+
+	// CapabilityDetection makes operations the server is known not to support
+	// fail with ErrUnsupportedByServer. See Capabilities.
+	CapabilityDetection bool
+	capabilities        capabilityProbe
+
+	// Sandbox, when set, answers writes locally or limits recipients.
+	Sandbox *Sandbox
+
+	// Preview holds the captured emails in preview mode, served on
+	// PreviewAddr until Close is called.
+	Preview       *PreviewEmails
+	PreviewAddr   string
+	previewServer *previewServer
+
+	DefaultFrom          string
+	DefaultContactBookId string
 }
 
-func NewClient() (*Client, error) {
-	ApiKey := os.Getenv(ENV_KEY_API_KEY)
-	baseURL := GetEnvOrDefault(ENV_KEY_BASE_URL, DEFAULT_BASE_URL)
+func NewClient(opts ...ClientOption) (*Client, error) {
+	options := new(clientOptions)
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	settings, err := resolveSettings(options)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := options.previewMode || strings.EqualFold(os.Getenv(ENV_KEY_MODE), MODE_PREVIEW)
 
-	if strings.TrimSpace(ApiKey) == "" {
-		return nil, fmt.Errorf("no value found for API Key")
+	credentials := options.credentials
+	if credentials == nil {
+		if strings.TrimSpace(settings.ApiKey) == "" && !preview {
+			return nil, fmt.Errorf("no value found for API Key")
+		}
+		credentials = StaticCredentials{Key: settings.ApiKey}
 	}
 
-	baseUrl, err := url.Parse(baseURL)
+	baseUrl, err := url.Parse(settings.BaseUrl)
 	if err != nil {
 		return nil, err
 	}
 
 	client := &Client{
-		ApiKey: ApiKey,
+		ApiKey: settings.ApiKey,
 		Client: &http.Client{
-			Timeout: time.Second * 30,
+			Timeout: settings.Timeout,
 			Transport: &UnsendTransport{
-				ApiKey: ApiKey,
+				ApiKey:      settings.ApiKey,
+				Credentials: credentials,
+				Base:        options.transport,
 			},
 		},
-		BaseUrl: baseUrl,
+		Credentials:          credentials,
+		BaseUrl:              baseUrl,
+		DefaultFrom:          settings.DefaultFrom,
+		DefaultContactBookId: settings.DefaultContactBookId,
 	}
 
+	client.Campaigns = &CampaignsImpl{Client: client}
 	client.Contacts = &ContactsImpl{Client: client}
 	client.Domains = &DomainsImpl{Client: client}
 	client.Emails = &EmailsImpl{Client: client}
 
+	if options.logger != nil {
+		client.Logger = options.logger
+		client.RedactFields = options.redactFields
+	}
+
+	client.RateLimiter = options.rateLimiter
+	client.RetryPolicy = options.retryPolicy
+	client.Metrics = options.metrics
+	client.CapabilityDetection = options.capabilityDetection
+	client.Tracer = options.tracer
+	if client.Tracer == nil {
+		client.Tracer = NoopTracer{}
+	}
+
+	if options.idempotency != nil {
+		client.AutoIdempotencyKeys = options.idempotency.AutoGenerate
+		client.IdempotencyCache = NewIdempotencyCache(options.idempotency.Window)
+	}
+
+	if options.recipientOverride != nil {
+		client.RecipientOverride = options.recipientOverride
+	} else if to := os.Getenv(ENV_KEY_RECIPIENT_OVERRIDE); strings.TrimSpace(to) != "" {
+		client.RecipientOverride = &RecipientOverride{To: splitList(to)}
+	}
+
+	dryRun := options.dryRun || strings.EqualFold(os.Getenv(ENV_KEY_MODE), MODE_DRY_RUN)
+	if dryRun || len(options.allowedRecipientDomains) > 0 {
+		client.Sandbox = &Sandbox{
+			DryRun:                  dryRun,
+			AllowedRecipientDomains: options.allowedRecipientDomains,
+		}
+	}
+
+	if preview {
+		addr := firstNonEmpty(options.previewAddr, os.Getenv(ENV_KEY_PREVIEW_ADDR))
+		client.Preview = NewPreviewEmails()
+		client.Preview.DefaultFrom = settings.DefaultFrom
+
+		server, err := startPreviewServer(firstNonEmpty(addr, DEFAULT_PREVIEW_ADDR), client.Preview)
+		if err != nil && addr == "" {
+			// Another client, such as one in a parallel test, has the default
+			// port, so take any free one. PreviewAddr says which.
+			server, err = startPreviewServer("127.0.0.1:0", client.Preview)
+		}
+		if err != nil {
+			return nil, err
+		}
+		client.Emails = client.Preview
+		client.PreviewAddr = server.listener.Addr().String()
+		client.previewServer = server
+		if client.Logger != nil {
+			client.Logger.Info("unsend preview mode, emails are not sent", "url", "http://"+client.PreviewAddr)
+		}
+	} else if options.verifySender {
+		client.SenderVerifier = NewSenderVerifier(client.Domains, options.senderVerificationTTL)
+	}
+
 	return client, nil
 }
 
+// Close stops the preview server, if one was started. Clients that aren't in
+// preview mode hold no resources and don't need closing.
+func (c *Client) Close() error {
+	if c.previewServer == nil {
+		return nil
+	}
+	return c.previewServer.Close()
+}
+
 func (c *Client) NewRequest(method, urlAsString string, body interface{}) (*http.Request, error) {
+	return c.NewRequestWithContext(context.Background(), method, urlAsString, body)
+}
+
+func (c *Client) NewRequestWithContext(ctx context.Context, method, urlAsString string, body interface{}) (*http.Request, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
 	url, err := c.BaseUrl.Parse(urlAsString)
 	if err != nil {
 		return nil, err
@@ -67,7 +199,7 @@ func (c *Client) NewRequest(method, urlAsString string, body interface{}) (*http
 	}
 
 	var req *http.Request
-	req, err = http.NewRequestWithContext(context.Background(), method, url.String(), requestBody)
+	req, err = http.NewRequestWithContext(ctx, method, url.String(), requestBody)
 	if err != nil {
 		return nil, err
 	}
@@ -76,32 +208,178 @@ func (c *Client) NewRequest(method, urlAsString string, body interface{}) (*http
 }
 
 func (c *Client) Execute(req *http.Request, result interface{}) error {
-	resp, err := c.Client.Do(req)
+	generatedKey, err := c.applyIdempotencyKey(req)
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
-	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
+	start := time.Now()
+	req, span := c.startSpan(req)
+	exchange, err := c.execute(req, result, !generatedKey)
+	if err == nil {
+		c.logSchemaDrift(req, result)
+	}
+	c.endSpan(span, exchange, result, err)
+	fillResponseMeta(req.Context(), exchange, time.Since(start))
+	return err
+}
+
+// execute runs the request pipeline. The exchange is nil when the request
+// was refused before calling the API, and local when it was answered without
+// calling it. Only keys that can be sent again are looked up in the
+// idempotency cache.
+func (c *Client) execute(req *http.Request, result interface{}, cacheable bool) (*exchange, error) {
+	if err := c.checkCapabilities(req); err != nil {
+		return nil, err
+	}
+
+	if c.RecipientOverride != nil {
+		if err := c.RecipientOverride.apply(c, req); err != nil {
+			return nil, fmt.Errorf("request failed: %w", err)
+		}
+	}
+
+	if c.Sandbox != nil {
+		id, err := c.Sandbox.intercept(c, req)
+		if err != nil {
+			return nil, fmt.Errorf("request failed: %w", err)
+		}
+		if id != "" {
+			syntheticResponse(result, requestBody(req), id)
+			return &exchange{local: true}, nil
+		}
+	}
+
+	if key := req.Header.Get(IDEMPOTENCY_KEY_HEADER); key != "" && cacheable && c.IdempotencyCache != nil {
+		cached, finish, err := c.IdempotencyCache.begin(req.Context(), key, idempotencyFingerprint(req))
+		if err != nil {
+			return nil, fmt.Errorf("request failed: %w", err)
+		}
+		if cached != nil {
+			if c.Logger != nil {
+				c.Logger.DebugContext(req.Context(), "unsend request replayed from idempotency cache",
+					"method", req.Method, "path", req.URL.Path)
+			}
+			return &exchange{local: true}, unmarshalResponse(cached, result)
+		}
+
+		exchange, err := c.observedSend(req)
+		finish(exchange.body, err == nil)
+		if err != nil {
+			return exchange, err
+		}
+		return exchange, unmarshalResponse(exchange.body, result)
+	}
+
+	exchange, err := c.observedSend(req)
 	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
+		return exchange, err
+	}
+	return exchange, unmarshalResponse(exchange.body, result)
+}
+
+// exchange is what send saw: the last response and how many attempts it made.
+// A local exchange was answered by the sandbox or idempotency cache instead.
+type exchange struct {
+	body     []byte
+	response *http.Response
+	attempts int
+	local    bool
+}
+
+// observedSend sends req and reports the call to Metrics.
+func (c *Client) observedSend(req *http.Request) (*exchange, error) {
+	start := time.Now()
+	exchange, err := c.send(req)
+	c.observeRequest(req, exchange, time.Since(start), err)
+	return exchange, err
+}
+
+// send performs req, retrying according to RetryPolicy, until it gets a 2xx
+// response. The exchange is returned even when err is set.
+func (c *Client) send(req *http.Request) (*exchange, error) {
+	ctx := req.Context()
+	group := endpointGroup(req.URL.Path)
+	attempts := c.RetryPolicy.attempts(req)
+	result := &exchange{}
+
+	for attempt := 1; ; attempt++ {
+		attemptReq, err := rewindRequest(req, attempt)
+		if err != nil {
+			return result, fmt.Errorf("request failed: %w", err)
+		}
+
+		if c.RateLimiter != nil {
+			if err := c.RateLimiter.Wait(ctx, group); err != nil {
+				return result, fmt.Errorf("request failed: %w", err)
+			}
+		}
+
+		start := time.Now()
+		resp, err := c.Client.Do(attemptReq)
+		result.attempts = attempt
+		result.response = resp
+		if c.RateLimiter != nil {
+			c.RateLimiter.Observe(group, resp)
+		}
+
+		var respBody []byte
+		if err != nil {
+			err = fmt.Errorf("request failed: %w", err)
+		} else {
+			respBody, err = io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				err = fmt.Errorf("failed to read response body: %w", err)
+			}
+		}
+		result.body = respBody
+		c.logExchange(attemptReq, resp, respBody, time.Since(start), attempt, err)
+
+		if err == nil {
+			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+				return result, nil
+			}
+			err = &APIError{StatusCode: resp.StatusCode, Body: string(respBody), Header: resp.Header}
+		}
+
+		if attempt >= attempts || !c.RetryPolicy.shouldRetry(ctx, err) {
+			return result, err
+		}
+		if sleepContext(ctx, c.RetryPolicy.backoff(attempt, resp)) != nil {
+			return result, err
+		}
+	}
+}
+
+// rewindRequest returns req for the first attempt and a copy with a fresh body
+// for retries.
+func rewindRequest(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 1 {
+		return req, nil
 	}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("received non-2xx response: %d - %s", resp.StatusCode, string(respBody))
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		retry.Body = body
 	}
+	return retry, nil
+}
 
-	if err := json.Unmarshal(respBody, result); err != nil {
+func unmarshalResponse(body []byte, result interface{}) error {
+	if err := json.Unmarshal(body, result); err != nil {
 		return fmt.Errorf("failed to unmarshal response: %w", err)
 	}
-
 	return nil
 }
 
 func GetEnvOrDefault(envVariable string, defaultValue string) string {
 	val, ok := os.LookupEnv(envVariable)
 	if !ok {
-		fmt.Printf("%s not set. Using default value of %s\n", envVariable, defaultValue)
 		return defaultValue
 	} else {
 		return val
@@ -0,0 +1,328 @@
+// Code generated by openapigen from openapi/unsend.json. DO NOT EDIT.
+
+package unsend
+
+func (req GetEmailRequest) Validate() *ValidationError {
+	errors := new(ValidationError)
+	if req.EmailId == "" {
+		errors.Errors = append(errors.Errors, "'EmailId' is required")
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+func (req SendEmailRequest) Validate() *ValidationError {
+	errors := new(ValidationError)
+	if len(req.To) == 0 {
+		errors.Errors = append(errors.Errors, "'To' is required")
+	}
+
+	if req.From == "" {
+		errors.Errors = append(errors.Errors, "'From' is required")
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+func (req UpdateScheduleRequest) Validate() *ValidationError {
+	errors := new(ValidationError)
+	if req.EmailId == "" {
+		errors.Errors = append(errors.Errors, "'EmailId' is required")
+	}
+
+	if req.ScheduledAt == "" {
+		errors.Errors = append(errors.Errors, "'ScheduledAt' is required")
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+func (req CancelScheduleRequest) Validate() *ValidationError {
+	errors := new(ValidationError)
+	if req.EmailId == "" {
+		errors.Errors = append(errors.Errors, "'EmailId' is required")
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+func (req GetContactRequest) Validate() *ValidationError {
+	errors := new(ValidationError)
+	if req.ContactBookId == "" {
+		errors.Errors = append(errors.Errors, "'ContactBookId' is required")
+	}
+
+	if req.ContactId == "" {
+		errors.Errors = append(errors.Errors, "'ContactId' is required")
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+func (req CreateContactRequest) Validate() *ValidationError {
+	errors := new(ValidationError)
+	if req.ContactBookId == "" {
+		errors.Errors = append(errors.Errors, "'ContactBookId' is required")
+	}
+
+	if req.Email == "" {
+		errors.Errors = append(errors.Errors, "'Email' is required")
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+func (req UpsertContactRequest) Validate() *ValidationError {
+	errors := new(ValidationError)
+	if req.ContactBookId == "" {
+		errors.Errors = append(errors.Errors, "'ContactBookId' is required")
+	}
+
+	if req.ContactId == "" {
+		errors.Errors = append(errors.Errors, "'ContactId' is required")
+	}
+
+	if req.Email == "" {
+		errors.Errors = append(errors.Errors, "'Email' is required")
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+func (req UpdateContactRequest) Validate() *ValidationError {
+	errors := new(ValidationError)
+	if req.ContactBookId == "" {
+		errors.Errors = append(errors.Errors, "'ContactBookId' is required")
+	}
+
+	if req.ContactId == "" {
+		errors.Errors = append(errors.Errors, "'ContactId' is required")
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+func (req DeleteContactRequest) Validate() *ValidationError {
+	errors := new(ValidationError)
+	if req.ContactBookId == "" {
+		errors.Errors = append(errors.Errors, "'ContactBookId' is required")
+	}
+
+	if req.ContactId == "" {
+		errors.Errors = append(errors.Errors, "'ContactId' is required")
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+func (req GetDomainRequest) Validate() *ValidationError {
+	errors := new(ValidationError)
+	if req.DomainId <= 0 {
+		errors.Errors = append(errors.Errors, "'DomainId' is required")
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+func (req CreateDomainRequest) Validate() *ValidationError {
+	errors := new(ValidationError)
+	if req.Name == "" {
+		errors.Errors = append(errors.Errors, "'Name' is required")
+	}
+
+	if req.Region == "" {
+		errors.Errors = append(errors.Errors, "'Region' is required")
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+func (req VerifyDomainRequest) Validate() *ValidationError {
+	errors := new(ValidationError)
+	if req.DomainId <= 0 {
+		errors.Errors = append(errors.Errors, "'DomainId' is required")
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+func (req DeleteDomainRequest) Validate() *ValidationError {
+	errors := new(ValidationError)
+	if req.DomainId <= 0 {
+		errors.Errors = append(errors.Errors, "'DomainId' is required")
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+func (req CreateCampaignRequest) Validate() *ValidationError {
+	errors := new(ValidationError)
+	if req.Name == "" {
+		errors.Errors = append(errors.Errors, "'Name' is required")
+	}
+
+	if req.From == "" {
+		errors.Errors = append(errors.Errors, "'From' is required")
+	}
+
+	if req.Subject == "" {
+		errors.Errors = append(errors.Errors, "'Subject' is required")
+	}
+
+	if req.ContactBookId == "" {
+		errors.Errors = append(errors.Errors, "'ContactBookId' is required")
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+func (req GetCampaignRequest) Validate() *ValidationError {
+	errors := new(ValidationError)
+	if req.CampaignId == "" {
+		errors.Errors = append(errors.Errors, "'CampaignId' is required")
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+func (req UpdateCampaignRequest) Validate() *ValidationError {
+	errors := new(ValidationError)
+	if req.CampaignId == "" {
+		errors.Errors = append(errors.Errors, "'CampaignId' is required")
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+func (req ScheduleCampaignRequest) Validate() *ValidationError {
+	errors := new(ValidationError)
+	if req.CampaignId == "" {
+		errors.Errors = append(errors.Errors, "'CampaignId' is required")
+	}
+
+	if req.ScheduledAt == "" {
+		errors.Errors = append(errors.Errors, "'ScheduledAt' is required")
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+func (req SendCampaignRequest) Validate() *ValidationError {
+	errors := new(ValidationError)
+	if req.CampaignId == "" {
+		errors.Errors = append(errors.Errors, "'CampaignId' is required")
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+func (req PauseCampaignRequest) Validate() *ValidationError {
+	errors := new(ValidationError)
+	if req.CampaignId == "" {
+		errors.Errors = append(errors.Errors, "'CampaignId' is required")
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+func (req ResumeCampaignRequest) Validate() *ValidationError {
+	errors := new(ValidationError)
+	if req.CampaignId == "" {
+		errors.Errors = append(errors.Errors, "'CampaignId' is required")
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+func (req DeleteCampaignRequest) Validate() *ValidationError {
+	errors := new(ValidationError)
+	if req.CampaignId == "" {
+		errors.Errors = append(errors.Errors, "'CampaignId' is required")
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
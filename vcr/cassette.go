@@ -0,0 +1,151 @@
+// Package vcr records HTTP interactions with the Unsend API into cassette
+// files and replays them, so tests run offline against real payload shapes.
+package vcr
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+// DEFAULT_SCRUB_HEADERS are never written to a cassette.
+var DEFAULT_SCRUB_HEADERS = []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// Cassette is the list of interactions saved in one file.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request. URL holds the path and query only, so a
+// cassette replays against any base URL.
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	// Body holds a JSON body as is. Text holds any other body.
+	Body json.RawMessage `json:"body,omitempty"`
+	Text string          `json:"text,omitempty"`
+}
+
+type Response struct {
+	StatusCode int             `json:"statusCode"`
+	Header     http.Header     `json:"header,omitempty"`
+	Body       json.RawMessage `json:"body,omitempty"`
+	Text       string          `json:"text,omitempty"`
+}
+
+// Load reads a cassette written by Save.
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, err
+	}
+	return &cassette, nil
+}
+
+// Save writes the cassette as indented JSON, creating the directory if needed.
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// splitBody stores body as JSON when it is valid JSON, and as text otherwise.
+func splitBody(body []byte) (json.RawMessage, string) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, ""
+	}
+	if json.Valid(body) {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, body); err == nil {
+			return compact.Bytes(), ""
+		}
+	}
+	return nil, string(body)
+}
+
+func joinBody(body json.RawMessage, text string) []byte {
+	if len(body) > 0 {
+		return body
+	}
+	return []byte(text)
+}
+
+// scrubHeader copies header, replacing the values of the given headers.
+func scrubHeader(header http.Header, names []string) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+
+	scrubbed := header.Clone()
+	for _, name := range names {
+		if scrubbed.Get(name) != "" {
+			scrubbed.Set(name, unsend.REDACTED)
+		}
+	}
+	return scrubbed
+}
+
+// scrubBody replaces every string under the given JSON keys, at any depth.
+// Unlike unsend.RedactJSON it keeps arrays and objects in place, so replayed
+// bodies still unmarshal into the SDK's types.
+func scrubBody(body []byte, fields []string) []byte {
+	if len(fields) == 0 || !json.Valid(body) {
+		return body
+	}
+
+	var document interface{}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return body
+	}
+
+	scrub := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		scrub[strings.ToLower(field)] = true
+	}
+
+	scrubbed, err := json.Marshal(scrubValue(document, scrub, false))
+	if err != nil {
+		return body
+	}
+	return scrubbed
+}
+
+func scrubValue(value interface{}, scrub map[string]bool, inside bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, inner := range v {
+			v[key] = scrubValue(inner, scrub, inside || scrub[strings.ToLower(key)])
+		}
+	case []interface{}:
+		for i, inner := range v {
+			v[i] = scrubValue(inner, scrub, inside)
+		}
+	case string:
+		if inside {
+			return unsend.REDACTED
+		}
+	}
+	return value
+}
@@ -0,0 +1,255 @@
+package vcr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"sync"
+
+	"github.com/QGeeDev/unsend-go"
+)
+
+type Mode string
+
+const (
+	// MODE_REPLAY serves requests from the cassette and fails any request it
+	// has no interaction for.
+	MODE_REPLAY Mode = "replay"
+	// MODE_RECORD sends every request and overwrites the cassette on Stop.
+	MODE_RECORD Mode = "record"
+	// MODE_REPLAY_OR_RECORD replays what the cassette has and records the rest.
+	MODE_REPLAY_OR_RECORD Mode = "replay-or-record"
+)
+
+var ErrInteractionNotFound = errors.New("vcr: no recorded interaction matches request")
+
+// Matcher reports whether a recorded request answers req. body is the scrubbed
+// request body, so it can be compared with the recorded one.
+type Matcher func(req *http.Request, body []byte, recorded Request) bool
+
+// MatchMethodAndURL matches on the method, path and query.
+func MatchMethodAndURL(req *http.Request, body []byte, recorded Request) bool {
+	return req.Method == recorded.Method && req.URL.RequestURI() == recorded.URL
+}
+
+// DefaultMatcher matches on the method, path, query and JSON body. Bodies are
+// compared by value, so key order doesn't matter.
+func DefaultMatcher(req *http.Request, body []byte, recorded Request) bool {
+	if !MatchMethodAndURL(req, body, recorded) {
+		return false
+	}
+
+	bodyJSON, bodyText := splitBody(body)
+	if len(bodyJSON) == 0 || len(recorded.Body) == 0 {
+		return bytes.Equal(bodyJSON, recorded.Body) && bodyText == recorded.Text
+	}
+	return jsonEqual(bodyJSON, recorded.Body)
+}
+
+type Options struct {
+	// Mode defaults to MODE_REPLAY.
+	Mode Mode
+	// Base sends requests that aren't replayed. Defaults to
+	// http.DefaultTransport.
+	Base    http.RoundTripper
+	Matcher Matcher
+	// ScrubFields are JSON keys whose string values are replaced in recorded
+	// bodies. Defaults to unsend.DEFAULT_REDACT_FIELDS; set an empty slice to
+	// keep bodies as they are.
+	ScrubFields []string
+	// ScrubHeaders are replaced in recorded headers, in addition to
+	// DEFAULT_SCRUB_HEADERS.
+	ScrubHeaders []string
+}
+
+// Recorder is an http.RoundTripper that records to or replays from a cassette
+// file. Each recorded interaction is replayed at most once, in order, so a
+// cassette can hold several answers to the same request.
+type Recorder struct {
+	path    string
+	options Options
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+	changed  bool
+}
+
+// New opens the cassette at path. In MODE_REPLAY the file must exist.
+func New(path string, options Options) (*Recorder, error) {
+	if options.Mode == "" {
+		options.Mode = MODE_REPLAY
+	}
+	if options.Base == nil {
+		options.Base = http.DefaultTransport
+	}
+	if options.Matcher == nil {
+		options.Matcher = DefaultMatcher
+	}
+	if options.ScrubFields == nil {
+		options.ScrubFields = unsend.DEFAULT_REDACT_FIELDS
+	}
+	options.ScrubHeaders = append(append([]string(nil), DEFAULT_SCRUB_HEADERS...), options.ScrubHeaders...)
+
+	recorder := &Recorder{path: path, options: options, cassette: &Cassette{}}
+
+	switch options.Mode {
+	case MODE_RECORD:
+	case MODE_REPLAY, MODE_REPLAY_OR_RECORD:
+		cassette, err := Load(path)
+		if err != nil && !(options.Mode == MODE_REPLAY_OR_RECORD && errors.Is(err, os.ErrNotExist)) {
+			return nil, fmt.Errorf("vcr: failed to load cassette: %w", err)
+		}
+		if cassette != nil {
+			recorder.cassette = cassette
+		}
+	default:
+		return nil, fmt.Errorf("vcr: unknown mode %q", options.Mode)
+	}
+
+	recorder.used = make([]bool, len(recorder.cassette.Interactions))
+	return recorder, nil
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	scrubbed := scrubBody(body, r.options.ScrubFields)
+
+	if r.options.Mode != MODE_RECORD {
+		if interaction, ok := r.replay(req, scrubbed); ok {
+			return interaction.Response.toHTTP(req), nil
+		}
+		if r.options.Mode == MODE_REPLAY {
+			return nil, fmt.Errorf("%w: %s %s", ErrInteractionNotFound, req.Method, req.URL.RequestURI())
+		}
+	}
+
+	resp, err := r.options.Base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    req.URL.RequestURI(),
+			Header: scrubHeader(req.Header, r.options.ScrubHeaders),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     scrubHeader(resp.Header, r.options.ScrubHeaders),
+		},
+	}
+	interaction.Request.Body, interaction.Request.Text = splitBody(scrubbed)
+	interaction.Response.Body, interaction.Response.Text = splitBody(scrubBody(respBody, r.options.ScrubFields))
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.used = append(r.used, true)
+	r.changed = true
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// Stop saves the cassette if anything was recorded.
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.changed {
+		return nil
+	}
+	if err := r.cassette.Save(r.path); err != nil {
+		return fmt.Errorf("vcr: failed to save cassette: %w", err)
+	}
+	r.changed = false
+	return nil
+}
+
+// Cassette returns the interactions loaded and recorded so far.
+func (r *Recorder) Cassette() Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return Cassette{Interactions: append([]Interaction(nil), r.cassette.Interactions...)}
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (Interaction, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if !r.used[i] && r.options.Matcher(req, body, interaction.Request) {
+			r.used[i] = true
+			return interaction, true
+		}
+	}
+	return Interaction{}, false
+}
+
+func (r Response) toHTTP(req *http.Request) *http.Response {
+	body := joinBody(r.Body, r.Text)
+	header := r.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// readRequestBody reads the body without consuming it for the base transport.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return io.ReadAll(body)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func jsonEqual(a, b []byte) bool {
+	var left, right interface{}
+	if json.Unmarshal(a, &left) != nil || json.Unmarshal(b, &right) != nil {
+		return false
+	}
+	return reflect.DeepEqual(left, right)
+}
@@ -0,0 +1,142 @@
+package vcr_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/QGeeDev/unsend-go"
+	"github.com/QGeeDev/unsend-go/vcr"
+)
+
+func newClient(t *testing.T, baseUrl string, recorder *vcr.Recorder) *unsend.Client {
+	t.Helper()
+
+	client, err := unsend.NewClient(
+		unsend.WithApiKey("secret-key"),
+		unsend.WithBaseUrl(baseUrl),
+		unsend.WithConfigFile(filepath.Join(t.TempDir(), "missing.toml")),
+		unsend.WithTransport(recorder),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	return client
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		switch r.Method {
+		case http.MethodPost:
+			w.Write([]byte(`{"emailId": "email123"}`))
+		default:
+			w.Write([]byte(`{"id": "email123", "to": ["alice@example.com"], "from": "hello@unsend.dev", "subject": "Hi"}`))
+		}
+	}))
+
+	path := filepath.Join(t.TempDir(), "cassettes", "emails.json")
+	request := unsend.SendEmailRequest{To: []string{"alice@example.com"}, From: "hello@unsend.dev", Subject: "Hi", Text: "Hi"}
+
+	recorder, err := vcr.New(path, vcr.Options{Mode: vcr.MODE_RECORD})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	client := newClient(t, server.URL, recorder)
+
+	sent, err := client.Emails.SendEmail(context.Background(), request)
+	if err != nil || sent.EmailId != "email123" {
+		t.Fatalf("expected recorded send, got %+v, %v", sent, err)
+	}
+	email, err := client.Emails.GetEmail(context.Background(), unsend.GetEmailRequest{EmailId: "email123"})
+	if err != nil || email.To[0] != "alice@example.com" {
+		t.Fatalf("expected live response while recording, got %+v, %v", email, err)
+	}
+	if err := recorder.Stop(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	server.Close()
+
+	cassette, err := vcr.Load(path)
+	if err != nil || len(cassette.Interactions) != 2 {
+		t.Fatalf("expected 2 saved interactions, got %+v, %v", cassette, err)
+	}
+	saved := cassette.Interactions[0]
+	if saved.Request.Header.Get("Authorization") != unsend.REDACTED {
+		t.Errorf("expected API key to be scrubbed, got %q", saved.Request.Header.Get("Authorization"))
+	}
+	if strings.Contains(string(saved.Request.Body), "alice@example.com") || strings.Contains(string(cassette.Interactions[1].Response.Body), "alice@example.com") {
+		t.Errorf("expected recipients to be scrubbed, got %s", saved.Request.Body)
+	}
+
+	replayer, err := vcr.New(path, vcr.Options{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	client = newClient(t, "http://unsend.invalid/api", replayer)
+
+	sent, err = client.Emails.SendEmail(context.Background(), request)
+	if err != nil || sent.EmailId != "email123" {
+		t.Fatalf("expected replayed send, got %+v, %v", sent, err)
+	}
+	email, err = client.Emails.GetEmail(context.Background(), unsend.GetEmailRequest{EmailId: "email123"})
+	if err != nil || email.Subject != unsend.REDACTED || len(email.To) != 1 || email.To[0] != unsend.REDACTED {
+		t.Fatalf("expected replayed email with scrubbed recipients and subject, got %+v, %v", email, err)
+	}
+
+	_, err = client.Emails.GetEmail(context.Background(), unsend.GetEmailRequest{EmailId: "email123"})
+	if !errors.Is(err, vcr.ErrInteractionNotFound) {
+		t.Errorf("expected interactions to be replayed once, got %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("expected 2 live calls, got %d", calls.Load())
+	}
+}
+
+func TestReplayOrRecord(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		io.WriteString(w, "pong")
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "ping.json")
+	for i := 0; i < 2; i++ {
+		recorder, err := vcr.New(path, vcr.Options{Mode: vcr.MODE_REPLAY_OR_RECORD, Matcher: vcr.MatchMethodAndURL})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		resp, err := (&http.Client{Transport: recorder}).Get(server.URL + "/ping?n=1")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if string(body) != "pong" {
+			t.Errorf("expected text body, got %q", body)
+		}
+
+		if err := recorder.Stop(); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("expected the second run to replay, got %d calls", calls.Load())
+	}
+}
+
+func TestReplayMissingCassette(t *testing.T) {
+	if _, err := vcr.New(filepath.Join(t.TempDir(), "missing.json"), vcr.Options{}); err == nil {
+		t.Errorf("expected an error for a missing cassette in replay mode")
+	}
+}